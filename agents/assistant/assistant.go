@@ -21,6 +21,17 @@ func NewAssistant(opts ...AssistantOption) (*Assistant, error) {
 		return assistant, nil
 	}
 
+	created, err := createAssistant(assistant)
+	if err != nil {
+		return nil, err
+	}
+
+	assistant.ID = created.ID
+
+	return assistant, nil
+}
+
+func createAssistant(assistant *Assistant) (*Assistant, error) {
 	url := fmt.Sprintf("%s/assistants", BaseURL)
 
 	bodyJSON, err := json.Marshal(assistant)
@@ -36,19 +47,37 @@ func NewAssistant(opts ...AssistantOption) (*Assistant, error) {
 	if err != nil {
 		return nil, err
 	}
-	var assistantResponse Assistant
-	if err := json.Unmarshal(respBody, &assistantResponse); err != nil {
+
+	var response Assistant
+	if err := json.Unmarshal(respBody, &response); err != nil {
 		return nil, err
 	}
+	return &response, nil
+}
 
-	assistant.ID = assistantResponse.ID
+// Returns the first page of assistants. Use IterateAssistants to walk all the pages.
+func (a *Assistant) ListAssistants(opts ...ListOption) ([]Assistant, error) {
+	response, err := listAssistants(opts...)
+	if err != nil {
+		return nil, err
+	}
 
-	return assistant, nil
+	return response.Data, nil
 }
 
-// Returns a list of assistants.
-func (a *Assistant) ListAssistants() ([]Assistant, error) {
-	url := fmt.Sprintf("%s/assistants", BaseURL)
+// IterateAssistants returns an iterator over all the assistants.
+func IterateAssistants(opts ...ListOption) *Iterator[Assistant] {
+	return NewIterator(func(opts ...ListOption) (*Page[Assistant], error) {
+		response, err := listAssistants(opts...)
+		if err != nil {
+			return nil, err
+		}
+		return &Page[Assistant]{Data: response.Data, HasMore: response.HasMore, LastID: response.LastId}, nil
+	}, opts...)
+}
+
+func listAssistants(opts ...ListOption) (*AssistantResponse, error) {
+	url := fmt.Sprintf("%s/assistants%s", BaseURL, ListQuery(opts...))
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
@@ -65,7 +94,7 @@ func (a *Assistant) ListAssistants() ([]Assistant, error) {
 		return nil, err
 	}
 
-	return response.Data, nil
+	return &response, nil
 }
 
 // Retrieve assistan
@@ -93,14 +122,18 @@ func (a *Assistant) RetrieveAssistant() (*Assistant, error) {
 
 // Modifies an assistant.
 func (a *Assistant) UpdateAssistant(assistnt Assistant) (*Assistant, error) {
-	url := fmt.Sprintf("%s/assistants/%s", BaseURL, a.ID)
+	return updateAssistant(a.ID, assistnt)
+}
+
+func updateAssistant(id string, body any) (*Assistant, error) {
+	url := fmt.Sprintf("%s/assistants/%s", BaseURL, id)
 
-	bodyJSON, err := json.Marshal(assistnt)
+	bodyJSON, err := json.Marshal(body)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequest("PATCH", url, bytes.NewBuffer(bodyJSON))
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(bodyJSON))
 	if err != nil {
 		return nil, err
 	}
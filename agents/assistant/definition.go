@@ -0,0 +1,287 @@
+package assistant
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/tools"
+	"gopkg.in/yaml.v3"
+)
+
+// DefinitionKeyMetadata is the metadata key Sync stamps on the assistants it
+// manages, holding the key of their Definition.
+const DefinitionKeyMetadata = "definition_key"
+
+// Definition is an assistant declared in a YAML or JSON file:
+//
+//	name: Calculator
+//	model: gpt-4o-mini
+//	instructions: You solve math expressions with the calculator tool.
+//	tools: [calculator, code_interpreter]
+//	tool_resources:
+//	  code_interpreter:
+//	    file_ids: [file-abc123]
+//	metadata:
+//	  team: finance
+//
+// Tools are the names of tools in a ToolRegistry, or the built-in
+// code_interpreter and file_search tools.
+type Definition struct {
+	// Key identifies the assistant across deploys. Defaults to Name.
+	Key           string            `json:"key,omitempty"`
+	Name          string            `json:"name"`
+	Model         string            `json:"model"`
+	Description   string            `json:"description,omitempty"`
+	Instructions  string            `json:"instructions,omitempty"`
+	Tools         []string          `json:"tools,omitempty"`
+	ToolResources *ToolResource     `json:"tool_resources,omitempty"`
+	Temperature   *float64          `json:"temperature,omitempty"`
+	TopP          *float64          `json:"top_p,omitempty"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+}
+
+// ToolRegistry maps tool names to the tools a Definition can reference.
+type ToolRegistry map[string]tools.Tool
+
+// NewToolRegistry registers the tools by their name.
+func NewToolRegistry(tools ...tools.Tool) ToolRegistry {
+	registry := ToolRegistry{}
+	for _, tool := range tools {
+		registry[tool.Name()] = tool
+	}
+	return registry
+}
+
+func (r ToolRegistry) lookup(name string) tools.Tool {
+	if tool, ok := r[name]; ok {
+		return tool
+	}
+	for registered, tool := range r {
+		if FormatString(registered) == name {
+			return tool
+		}
+	}
+	return nil
+}
+
+// LoadDefinition reads a Definition from a YAML or JSON file.
+func LoadDefinition(path string) (*Definition, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	definition, err := ParseDefinition(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return definition, nil
+}
+
+// ParseDefinition decodes a Definition from YAML or JSON, which is also YAML.
+func ParseDefinition(data []byte) (*Definition, error) {
+	// The YAML is converted to JSON so both formats use the json tags of the
+	// models, such as those of ToolResource.
+	var raw any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid definition: %w", err)
+	}
+	bodyJSON, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid definition: %w", err)
+	}
+
+	var definition Definition
+	decoder := json.NewDecoder(bytes.NewReader(bodyJSON))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&definition); err != nil {
+		return nil, fmt.Errorf("invalid definition: %w", err)
+	}
+
+	if definition.Name == "" {
+		return nil, fmt.Errorf("invalid definition: name is required")
+	}
+	if definition.Model == "" {
+		return nil, fmt.Errorf("invalid definition %s: model is required", definition.Name)
+	}
+	return &definition, nil
+}
+
+// Assistant builds the assistant of the definition, stamped with its key. The
+// tools are looked up in registry.
+func (d *Definition) Assistant(registry ToolRegistry) (*Assistant, error) {
+	key := d.Key
+	if key == "" {
+		key = d.Name
+	}
+	metadata := map[string]string{DefinitionKeyMetadata: key}
+	for k, v := range d.Metadata {
+		metadata[k] = v
+	}
+
+	assistant := &Assistant{
+		Model:        d.Model,
+		Name:         d.Name,
+		Description:  d.Description,
+		Instructions: d.Instructions,
+		ToolResource: d.ToolResources,
+		Temperature:  d.Temperature,
+		TopP:         d.TopP,
+		Metadata:     metadata,
+	}
+
+	// always set, so tools removed from the definition are removed by Sync
+	definitions := make([]llms.Tool, 0, len(d.Tools))
+	for _, name := range d.Tools {
+		switch ToolType(name) {
+		case CodeInterpreterType, ToolTypeFileSearch:
+			definitions = append(definitions, llms.Tool{Type: name})
+			continue
+		}
+
+		tool := registry.lookup(name)
+		if tool == nil {
+			return nil, fmt.Errorf("definition %s: tool not found in registry: %s", d.Name, name)
+		}
+		withTool := &Assistant{}
+		WithTools([]tools.Tool{tool})(withTool)
+		definitions = append(definitions, (*withTool.Tools)...)
+	}
+	assistant.Tools = &definitions
+
+	return assistant, nil
+}
+
+// SyncAction is what Sync did to the remote assistant.
+type SyncAction string
+
+const (
+	SyncCreated   SyncAction = "created"
+	SyncUpdated   SyncAction = "updated"
+	SyncUnchanged SyncAction = "unchanged"
+)
+
+// SyncResult is the outcome of Sync.
+type SyncResult struct {
+	Assistant *Assistant
+	Action    SyncAction
+	// Changes are the fields that differed from the remote assistant.
+	Changes []string
+}
+
+// Sync makes the remote assistant match the definition. The assistant is the
+// one stamped with the key of the definition or, the first time, the one with
+// its name. It is updated when it differs, and created when there is none.
+func Sync(ctx context.Context, d *Definition, registry ToolRegistry) (*SyncResult, error) {
+	desired, err := d.Assistant(registry)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := findDefined(ctx, desired)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find assistant %s: %w", d.Name, err)
+	}
+
+	if existing == nil {
+		created, err := createAssistant(desired)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create assistant %s: %w", d.Name, err)
+		}
+		return &SyncResult{Assistant: created, Action: SyncCreated}, nil
+	}
+
+	changes := diffAssistant(existing, desired)
+	if len(changes) == 0 {
+		return &SyncResult{Assistant: existing, Action: SyncUnchanged}, nil
+	}
+
+	updated, err := updateAssistant(existing.ID, syncUpdate{Assistant: desired, Description: desired.Description, Instructions: desired.Instructions})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update assistant %s: %w", d.Name, err)
+	}
+	return &SyncResult{Assistant: updated, Action: SyncUpdated, Changes: changes}, nil
+}
+
+// syncUpdate is the body of the update made by Sync. The description and
+// instructions are sent even when empty, so removing them from the definition
+// clears them.
+type syncUpdate struct {
+	*Assistant
+	Description  string `json:"description"`
+	Instructions string `json:"instructions"`
+}
+
+// findDefined looks for the assistant stamped with the key of desired, or else
+// the first one with its name.
+func findDefined(ctx context.Context, desired *Assistant) (*Assistant, error) {
+	key := desired.Metadata[DefinitionKeyMetadata]
+
+	var byName *Assistant
+	it := IterateAssistants(WithLimit(100))
+	for it.Next(ctx) {
+		current := it.Current()
+		if current.Metadata[DefinitionKeyMetadata] == key {
+			return &current, nil
+		}
+		if byName == nil && current.Name == desired.Name && current.Metadata[DefinitionKeyMetadata] == "" {
+			byName = &current
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	return byName, nil
+}
+
+// diffAssistant returns the fields of desired that differ from existing.
+// Unset tool resources, temperature and top_p are left as they are.
+func diffAssistant(existing, desired *Assistant) []string {
+	var changes []string
+	changed := func(field string, differs bool) {
+		if differs {
+			changes = append(changes, field)
+		}
+	}
+
+	changed("model", existing.Model != desired.Model)
+	changed("name", existing.Name != desired.Name)
+	changed("description", existing.Description != desired.Description)
+	changed("instructions", existing.Instructions != desired.Instructions)
+	changed("tools", !sameJSON(toolsOf(existing), toolsOf(desired)))
+	changed("tool_resources", desired.ToolResource != nil && !sameJSON(existing.ToolResource, desired.ToolResource))
+	changed("temperature", desired.Temperature != nil && !sameFloat(existing.Temperature, desired.Temperature))
+	changed("top_p", desired.TopP != nil && !sameFloat(existing.TopP, desired.TopP))
+	changed("metadata", !reflect.DeepEqual(existing.Metadata, desired.Metadata))
+
+	return changes
+}
+
+func toolsOf(a *Assistant) []llms.Tool {
+	if a.Tools == nil {
+		return []llms.Tool{}
+	}
+	return *a.Tools
+}
+
+func sameFloat(a, b *float64) bool {
+	return a != nil && b != nil && *a == *b
+}
+
+// sameJSON compares the values as the API sees them, so the maps decoded from
+// a response equal the structs they were created from.
+func sameJSON(a, b any) bool {
+	normalize := func(v any) any {
+		bodyJSON, _ := json.Marshal(v)
+		var out any
+		_ = json.Unmarshal(bodyJSON, &out)
+		return out
+	}
+	return reflect.DeepEqual(normalize(a), normalize(b))
+}
@@ -0,0 +1,153 @@
+package assistant
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/tools"
+)
+
+type echoTool struct{}
+
+func (echoTool) Name() string                                         { return "Echo Tool" }
+func (echoTool) Description() string                                  { return "echoes the input" }
+func (echoTool) Call(_ context.Context, input string) (string, error) { return input, nil }
+
+var _ tools.Tool = echoTool{}
+
+const calculatorYAML = `
+name: Calculator
+model: gpt-4o-mini
+instructions: You solve math expressions.
+tools: [Echo Tool, code_interpreter]
+tool_resources:
+  code_interpreter:
+    file_ids: [file-1]
+temperature: 0.2
+metadata:
+  team: finance
+`
+
+func TestParseDefinition(t *testing.T) {
+	t.Parallel()
+
+	definition, err := ParseDefinition([]byte(calculatorYAML))
+	require.NoError(t, err)
+	assert.Equal(t, "Calculator", definition.Name)
+	assert.Equal(t, []string{"file-1"}, definition.ToolResources.CodeInterpreter.FileIDs)
+	assert.InDelta(t, 0.2, *definition.Temperature, 1e-9)
+
+	// JSON is YAML too
+	fromJSON, err := ParseDefinition([]byte(`{"name":"Calculator","model":"gpt-4o-mini","instructions":"You solve math expressions.",
+		"tools":["Echo Tool","code_interpreter"],"tool_resources":{"code_interpreter":{"file_ids":["file-1"]}},
+		"temperature":0.2,"metadata":{"team":"finance"}}`))
+	require.NoError(t, err)
+	assert.Equal(t, definition, fromJSON)
+
+	_, err = ParseDefinition([]byte("name: Calculator\nmodel: gpt-4o\nmodle: typo\n"))
+	require.Error(t, err)
+	_, err = ParseDefinition([]byte("name: Calculator\n"))
+	require.Error(t, err)
+
+	a, err := definition.Assistant(NewToolRegistry(echoTool{}))
+	require.NoError(t, err)
+	require.Len(t, *a.Tools, 2)
+	assert.Equal(t, "Echo_Tool", (*a.Tools)[0].Function.Name)
+	assert.Equal(t, "code_interpreter", (*a.Tools)[1].Type)
+	assert.Equal(t, map[string]string{"team": "finance", DefinitionKeyMetadata: "Calculator"}, a.Metadata)
+
+	_, err = definition.Assistant(ToolRegistry{})
+	require.ErrorContains(t, err, "tool not found in registry: Echo Tool")
+}
+
+func TestSync(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "calculator.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(calculatorYAML), 0o600))
+	definition, err := LoadDefinition(path)
+	require.NoError(t, err)
+	registry := NewToolRegistry(echoTool{})
+
+	// the remote assistants, by id
+	remote := map[string]Assistant{
+		"asst_other": {ID: "asst_other", Name: "Other", Model: "gpt-4o"},
+	}
+	var updates []map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/assistants":
+			var data []Assistant
+			for _, a := range remote {
+				data = append(data, a)
+			}
+			_ = json.NewEncoder(w).Encode(AssistantResponse{Data: data})
+		case r.Method == http.MethodPost && r.URL.Path == "/assistants":
+			var a Assistant
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&a))
+			a.ID = "asst_new"
+			remote[a.ID] = a
+			_ = json.NewEncoder(w).Encode(a)
+		case r.Method == http.MethodPost && r.URL.Path == "/assistants/asst_new":
+			body, _ := io.ReadAll(r.Body)
+			var update map[string]any
+			require.NoError(t, json.Unmarshal(body, &update))
+			updates = append(updates, update)
+
+			var a Assistant
+			require.NoError(t, json.Unmarshal(body, &a))
+			a.ID = "asst_new"
+			remote[a.ID] = a
+			_ = json.NewEncoder(w).Encode(a)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	baseURL := BaseURL
+	BaseURL = srv.URL
+	defer func() { BaseURL = baseURL }()
+
+	ctx := context.Background()
+	result, err := Sync(ctx, definition, registry)
+	require.NoError(t, err)
+	assert.Equal(t, SyncCreated, result.Action)
+	assert.Equal(t, "asst_new", result.Assistant.ID)
+
+	result, err = Sync(ctx, definition, registry)
+	require.NoError(t, err)
+	assert.Equal(t, SyncUnchanged, result.Action)
+	assert.Empty(t, updates)
+
+	definition.Instructions = "You solve math expressions step by step."
+	definition.Tools = []string{"code_interpreter"}
+	result, err = Sync(ctx, definition, registry)
+	require.NoError(t, err)
+	assert.Equal(t, SyncUpdated, result.Action)
+	assert.Equal(t, []string{"instructions", "tools"}, result.Changes)
+	require.Len(t, updates, 1)
+	assert.NotContains(t, updates[0], "id")
+	assert.Equal(t, "You solve math expressions step by step.", remote["asst_new"].Instructions)
+	assert.Len(t, remote, 2)
+
+	// removing the instructions clears them, so the next sync has nothing to do
+	definition.Instructions = ""
+	result, err = Sync(ctx, definition, registry)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"instructions"}, result.Changes)
+	require.Len(t, updates, 2)
+	assert.Equal(t, "", updates[1]["instructions"])
+
+	result, err = Sync(ctx, definition, registry)
+	require.NoError(t, err)
+	assert.Equal(t, SyncUnchanged, result.Action)
+	assert.Len(t, updates, 2)
+}
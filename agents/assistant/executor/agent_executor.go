@@ -2,28 +2,65 @@ package executor
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/devalexandre/langsmithgo"
 	"github.com/devalexandre/mylangchaingo"
 	"github.com/devalexandre/mylangchaingo/agents/assistant"
 	"github.com/devalexandre/mylangchaingo/agents/assistant/message"
 	"github.com/devalexandre/mylangchaingo/agents/assistant/runner"
-	"github.com/devalexandre/mylangchaingo/agents/assistant/thread"
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/tools"
 
 	"github.com/google/uuid"
 	"log"
-	"net/http"
 	"os"
+	"strings"
 	"time"
 )
 
 // AgentExecutor is responsible for executing the agent with the provided tools
 type AgentExecutor struct {
-	Agent           *assistant.Assistant
-	Tools           []tools.Tool
-	langsmithClient *langsmithgo.Client
+	Agent        *assistant.Assistant
+	Tools        []tools.Tool
+	PollInterval time.Duration
+	// ThreadStore keeps the thread of each conversation, see SessionFor.
+	ThreadStore ThreadStore
+	// ThreadTTL is how long a conversation can be idle before its thread is deleted.
+	ThreadTTL time.Duration
+
+	limits             Limits
+	stepTrace          bool
+	callOptions        []llms.CallOption
+	approvalPolicies   map[string]ApprovalPolicy
+	defaultApproval    ApprovalPolicy
+	approver           Approver
+	toolTimeouts       map[string]time.Duration
+	defaultToolTimeout time.Duration
+	maxToolOutput      int
+	outputStrategy     OutputStrategy
+	events             chan<- Event
+	callbacksHandler   callbacks.Handler
+	langsmithClient    *langsmithgo.Client
+	sessionLocks       keyLocks
+}
+
+// Result is the outcome of a run of the agent.
+type Result struct {
+	Output   string
+	ThreadID string
+	RunID    string
+	// Steps taken by the assistant to produce the output, oldest first.
+	// Only set when the executor is created with WithStepTrace.
+	Steps []runner.RunStep
+	// Usage is the tokens used by the run, when reported by the model.
+	Usage *runner.Usage
+	// Files are the files generated by the code interpreter that the messages
+	// of the run refer to. The images only found in the run steps are added
+	// when the steps are listed, with WithStepTrace or when the agent has the
+	// code interpreter tool. See DownloadFiles.
+	Files []message.GeneratedFile
 }
 
 // NewAgentExecutor creates a new instance of AgentExecutor
@@ -54,97 +91,201 @@ func NewAgentExecutor(agent *assistant.Assistant, opts ...ExecutorOption) *Agent
 
 // Run executes the agent with the provided input and returns the response
 func (ae *AgentExecutor) Run(input string) (string, error) {
-	threads, err := thread.CreateThread()
+	result, err := ae.Invoke(input)
 	if err != nil {
-		return "", fmt.Errorf("failed to create thread: %w", err)
+		// a run stopped by its limits still has a partial answer
+		if result != nil {
+			return result.Output, err
+		}
+		return "", err
 	}
 
-	_, err = message.CreateMessage(threads.ID, "user", input)
-
-	if err != nil {
-		return "", fmt.Errorf("failed to add message: %w", err)
-	}
+	return result.Output, nil
+}
 
-	run, err := runner.CreateRun(ae.Agent.ID, threads.ID)
-	if err != nil {
-		return "", fmt.Errorf("failed to create run: %w", err)
-	}
-	response, err := ae.RetrieveThreadMessages(*run.Id, threads.ID)
+// Invoke executes the agent with the provided input and returns the response
+// along with the thread and run it was produced by.
+func (ae *AgentExecutor) Invoke(input string) (*Result, error) {
+	session, err := ae.NewSession()
 	if err != nil {
-		return "", fmt.Errorf("failed to retrieve thread messages: %w", err)
+		return nil, err
 	}
 
-	return response, nil
+	return session.Send(context.Background(), input)
 }
 
 // HandleToolsExecution handles the execution of tools when required
 
 func (ae *AgentExecutor) RetrieveThreadMessages(runID, threadID string) (string, error) {
+	ctx := context.Background()
+	if _, err := ae.waitRun(ctx, threadID, runID); err != nil {
+		return "", err
+	}
+
+	output, _, err := ae.runOutput(ctx, threadID, runID)
+	return output, err
+}
+
+// waitRun polls the run until it completes, executing the tools it asks for,
+// and returns the completed run.
+// When a limit is exceeded the run is cancelled and a *LimitError returned.
+func (ae *AgentExecutor) waitRun(ctx context.Context, threadID, runID string) (*runner.Runner, error) {
+	budget := ae.newBudget()
+	lastStatus := ""
 	for {
-		status, toolCalls, err := ae.CheckRunStatus(threadID, runID)
+		run, err := runner.RetrieveRun(threadID, runID)
 		if err != nil {
-			return "", err
+			return nil, err
+		}
+
+		var status string
+		if run.Status != nil {
+			status = *run.Status
+		}
+		if status != lastStatus {
+			ae.emit(ctx, Event{Type: EventStatusChanged, ThreadID: threadID, RunID: runID, Status: status})
+			lastStatus = status
+		}
+		if status != runner.StatusCompleted {
+			tokens, err := ae.runTokens(ctx, threadID, runID, run)
+			if err != nil {
+				return nil, err
+			}
+			if err := budget.usage(tokens); err != nil {
+				return nil, ae.stopRun(threadID, runID, err)
+			}
 		}
 
-		if status == "completed" {
-			break
-		} else if status == "requires_action" {
+		if status == runner.StatusCompleted {
+			return run, nil
+		} else if status == runner.StatusRequiresAction && run.RequiredAction != nil {
+			if err := budget.round(); err != nil {
+				return nil, ae.stopRun(threadID, runID, err)
+			}
 			// Identifica e executa ferramentas dinamicamente
-			err = ae.HandleToolsExecution(threadID, runID, toolCalls)
+			err = ae.handleTools(ctx, threadID, runID, run.RequiredAction.SubmitToolOutputs.ToolCalls, budget)
+			var limitErr *LimitError
+			if errors.As(err, &limitErr) {
+				return nil, ae.stopRun(threadID, runID, limitErr)
+			}
 			if err != nil {
-				return "", fmt.Errorf("failed to handle tools execution: %w", err)
+				return nil, fmt.Errorf("failed to handle tools execution: %w", err)
 			}
-		} else if status == "failed" {
-			return "", fmt.Errorf("run failed")
+			continue
+		} else if err := runFailure(run, status); err != nil {
+			return nil, err
 		}
 
-		time.Sleep(1 * time.Second)
+		if err := budget.elapsed(); err != nil {
+			return nil, ae.stopRun(threadID, runID, err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(ae.pollInterval()):
+		}
 	}
+}
 
-	// Recupera a resposta final do agente
-	messages, err := message.ListMessages(threadID)
-	if err != nil {
-		return "", err
+// runFailure returns why a run that ended without completing failed, or nil
+// when the run has not ended.
+func runFailure(run *runner.Runner, status string) error {
+	switch {
+	case status == runner.StatusFailed && run.LastError != nil:
+		return fmt.Errorf("run failed: %s: %s", run.LastError.Code, run.LastError.Message)
+	case status == runner.StatusFailed:
+		return fmt.Errorf("run failed")
+	case status == runner.StatusIncomplete && run.IncompleteDetails != nil:
+		return fmt.Errorf("run incomplete: %s", run.IncompleteDetails.Reason)
+	case status == runner.StatusCancelled || status == runner.StatusExpired || status == runner.StatusIncomplete:
+		return fmt.Errorf("run %s", status)
 	}
-	for _, msg := range messages.Data {
-		if msg.Role == "assistant" {
-			return msg.Content[0].Text.Value, nil
+	return nil
+}
+
+// runTokens returns the tokens used so far by the run. The usage of a run is
+// only reported once it ends, so while it runs the usage of its finished
+// steps is summed instead. The steps are only listed when MaxTokens is set.
+func (ae *AgentExecutor) runTokens(ctx context.Context, threadID, runID string, run *runner.Runner) (int, error) {
+	if run.Usage != nil {
+		return run.Usage.TotalTokens, nil
+	}
+	if ae.limits.MaxTokens <= 0 {
+		return 0, nil
+	}
+
+	tokens := 0
+	steps := runner.IterateRunSteps(threadID, runID, assistant.WithLimit(100))
+	for steps.Next(ctx) {
+		if usage := steps.Current().Usage; usage != nil {
+			tokens += usage.TotalTokens
 		}
 	}
+	if err := steps.Err(); err != nil {
+		return 0, fmt.Errorf("failed to list run steps: %w", err)
+	}
+	return tokens, nil
+}
 
-	return "", fmt.Errorf("no assistant message found")
+// stopRun cancels a run stopped by its limits. The run may have finished in
+// the meantime, so failing to cancel it is not an error. A streamed run can
+// be stopped before its ID is known, and is then left alone.
+func (ae *AgentExecutor) stopRun(threadID, runID string, limitErr error) error {
+	if runID != "" {
+		_, _ = runner.CancelRun(threadID, runID)
+	}
+	return limitErr
 }
 
-func (ae *AgentExecutor) CheckRunStatus(threadID, runID string) (string, []assistant.ToolCall, error) {
-	url := fmt.Sprintf("%s/threads/%s/runs/%s", assistant.BaseURL, threadID, runID)
+// runOutput returns the text of the assistant messages created by the run,
+// oldest first, and the files they refer to.
+func (ae *AgentExecutor) runOutput(ctx context.Context, threadID, runID string) (string, []message.GeneratedFile, error) {
+	// Retrieves only the messages produced by this run
+	messages := message.IterateMessages(threadID, assistant.WithRunID(runID), assistant.WithOrder("asc"))
 
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
+	var texts []string
+	var files []message.GeneratedFile
+	for messages.Next(ctx) {
+		msg := messages.Current()
+		if msg.Role == "assistant" {
+			texts = append(texts, msg.Content.Text())
+			files = append(files, msg.Content.GeneratedFiles()...)
+		}
+	}
+	if err := messages.Err(); err != nil {
 		return "", nil, err
 	}
+	if len(texts) == 0 {
+		return "", nil, fmt.Errorf("no assistant message found")
+	}
+
+	return strings.Join(texts, "\n"), files, nil
+}
 
-	respBody, err := assistant.Do(req)
+func (ae *AgentExecutor) pollInterval() time.Duration {
+	if ae.PollInterval > 0 {
+		return ae.PollInterval
+	}
+	return time.Second
+}
+
+func (ae *AgentExecutor) CheckRunStatus(threadID, runID string) (string, []assistant.ToolCall, error) {
+	run, err := runner.RetrieveRun(threadID, runID)
 	if err != nil {
 		return "", nil, err
 	}
-	var result struct {
-		Status         string `json:"status"`
-		RequiredAction *struct {
-			SubmitToolOutputs struct {
-				ToolCalls []assistant.ToolCall `json:"tool_calls"`
-			} `json:"submit_tool_outputs"`
-		} `json:"required_action"`
-	}
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		return "", nil, err
+
+	var status string
+	if run.Status != nil {
+		status = *run.Status
 	}
 
 	var toolCalls []assistant.ToolCall
-	if result.RequiredAction != nil {
-		toolCalls = result.RequiredAction.SubmitToolOutputs.ToolCalls
+	if run.RequiredAction != nil {
+		toolCalls = run.RequiredAction.SubmitToolOutputs.ToolCalls
 	}
 
-	return result.Status, toolCalls, nil
+	return status, toolCalls, nil
 }
 
 //func (ae *AgentExecutor) HandleToolsExecution(threadID, runID string, toolCalls []assistant.ToolCall) error {
@@ -217,30 +358,81 @@ func (ae *AgentExecutor) CheckRunStatus(threadID, runID string) (string, []assis
 //}
 
 func (ae *AgentExecutor) HandleToolsExecution(threadID, runID string, toolCalls []assistant.ToolCall) error {
-	for _, toolCall := range toolCalls {
-		tool := ae.findToolByName(toolCall.Function.Name)
-		if tool == nil {
-			return fmt.Errorf("tool not found: %s", toolCall.Function.Name)
-		}
+	return ae.handleTools(context.Background(), threadID, runID, toolCalls, nil)
+}
 
-		// extract __arg1 received in arguments
-		arg1, err := assistant.ExtractArg1(toolCall.Function.Arguments)
-		if err != nil {
-			return fmt.Errorf("failed to extract arg1: %w", err)
+func (ae *AgentExecutor) handleTools(ctx context.Context, threadID, runID string, toolCalls []assistant.ToolCall, budget *runBudget) error {
+	outputs, err := ae.toolOutputs(ctx, threadID, runID, toolCalls, budget)
+	if err != nil {
+		return err
+	}
+
+	// Submits the outputs of all the tools back to the agent in a single request
+	if _, err := runner.SubmitToolOutputs(threadID, runID, outputs); err != nil {
+		return fmt.Errorf("failed to submit tool outputs: %w", err)
+	}
+	return nil
+}
+
+// toolOutputs calls the tools requested by the run.
+func (ae *AgentExecutor) toolOutputs(ctx context.Context, threadID, runID string, toolCalls []assistant.ToolCall, budget *runBudget) ([]runner.ToolOutput, error) {
+	outputs := make([]runner.ToolOutput, 0, len(toolCalls))
+	for _, toolCall := range toolCalls {
+		call := ToolInvocation{
+			ThreadID:   threadID,
+			RunID:      runID,
+			ToolCallID: toolCall.ID,
+			Name:       toolCall.Function.Name,
+			Arguments:  toolCall.Function.Arguments,
 		}
-		// Executa a ferramenta
-		toolOutput, err := tool.Call(context.Background(), arg1)
-		if err != nil {
-			return fmt.Errorf("failed to execute tool %s: %w", tool.Name(), err)
+		if err := budget.toolCall(call); err != nil {
+			return nil, err
 		}
 
-		// Submete a saída da ferramenta de volta ao agente
-		err = assistant.SubmitToolOutput(threadID, runID, toolCall.ID, toolOutput)
+		toolOutput, err := ae.callTool(ctx, call)
 		if err != nil {
-			return fmt.Errorf("failed to submit tool output for tool %s: %w", tool.Name(), err)
+			return nil, err
 		}
+
+		outputs = append(outputs, runner.ToolOutput{ToolCallID: toolCall.ID, Output: toolOutput})
 	}
-	return nil
+	return outputs, nil
+}
+
+// callTool executes the tool requested by the model, once approved. It is
+// shared by the AgentExecutor and the LocalExecutor.
+func (ae *AgentExecutor) callTool(ctx context.Context, call ToolInvocation) (string, error) {
+	tool := ae.findToolByName(call.Name)
+	if tool == nil {
+		notFound := &ToolError{Tool: call.Name, Type: ToolErrorNotFound, Err: errors.New("no tool with this name")}
+		return notFound.Output(), nil
+	}
+
+	call, rejection, approved, err := ae.approve(ctx, call)
+	if err != nil {
+		return "", err
+	}
+
+	ae.emit(ctx, Event{Type: EventToolStart, ThreadID: call.ThreadID, RunID: call.RunID, Tool: &call})
+	if !approved {
+		ae.emit(ctx, Event{Type: EventToolEnd, ThreadID: call.ThreadID, RunID: call.RunID, Tool: &call, Output: rejection})
+		return rejection, nil
+	}
+
+	toolOutput, err := ae.executeTool(ctx, tool, call)
+	var toolErr *ToolError
+	if errors.As(err, &toolErr) {
+		// the model gets the error, so it can retry or answer without the tool
+		toolOutput = toolErr.Output()
+	} else if err == nil {
+		toolOutput = ae.limitOutput(ctx, call, toolOutput)
+	}
+	ae.emit(ctx, Event{Type: EventToolEnd, ThreadID: call.ThreadID, RunID: call.RunID, Tool: &call, Output: toolOutput, Err: err})
+
+	if toolErr != nil {
+		return toolOutput, nil
+	}
+	return toolOutput, err
 }
 
 // Busca a ferramenta registrada pelo nome
@@ -0,0 +1,145 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/devalexandre/mylangchaingo/agents/assistant"
+)
+
+// ApprovalPolicy decides whether a tool can run without a human approving it.
+type ApprovalPolicy int
+
+const (
+	// ApprovalAlways runs the tool without asking.
+	ApprovalAlways ApprovalPolicy = iota
+	// ApprovalNever rejects every call to the tool.
+	ApprovalNever
+	// ApprovalAsk asks the Approver before each call to the tool.
+	ApprovalAsk
+)
+
+// ToolInvocation is a tool call requested by the model during a run.
+type ToolInvocation struct {
+	ThreadID   string
+	RunID      string
+	ToolCallID string
+	Name       string
+	Arguments  string
+}
+
+// ApprovalDecision is the answer of a human to an ApprovalAsk tool call.
+type ApprovalDecision struct {
+	Approved bool
+	// Arguments replace the ones requested by the model when not empty.
+	Arguments string
+	// Reason is sent to the model when the call is rejected.
+	Reason string
+}
+
+// Approver asks a human to approve a tool call. It blocks until there is a
+// decision; meanwhile the run stays in requires_action. Runs of the
+// Assistants API expire after ten minutes in that status.
+type Approver func(ctx context.Context, call ToolInvocation) (ApprovalDecision, error)
+
+// PendingApproval is a tool call waiting for a decision in an ApprovalQueue.
+type PendingApproval struct {
+	Call     ToolInvocation
+	decision chan ApprovalDecision
+}
+
+// Approve lets the tool run. Non empty arguments replace the requested ones.
+func (p *PendingApproval) Approve(arguments string) {
+	p.decision <- ApprovalDecision{Approved: true, Arguments: arguments}
+}
+
+// Reject refuses the call, the reason is sent to the model instead of the tool output.
+func (p *PendingApproval) Reject(reason string) {
+	p.decision <- ApprovalDecision{Reason: reason}
+}
+
+// ApprovalQueue delivers the tool calls that need approval on a channel, for
+// applications that answer them from another goroutine, such as a UI handler.
+type ApprovalQueue struct {
+	requests chan *PendingApproval
+}
+
+// NewApprovalQueue returns a queue to use with WithApprover(queue.Approver).
+func NewApprovalQueue() *ApprovalQueue {
+	return &ApprovalQueue{requests: make(chan *PendingApproval)}
+}
+
+// Requests returns the channel of the calls waiting for a decision. Each one
+// must be approved or rejected exactly once.
+func (q *ApprovalQueue) Requests() <-chan *PendingApproval {
+	return q.requests
+}
+
+// Approver sends each call to the Requests channel and waits for its decision.
+func (q *ApprovalQueue) Approver(ctx context.Context, call ToolInvocation) (ApprovalDecision, error) {
+	pending := &PendingApproval{Call: call, decision: make(chan ApprovalDecision, 1)}
+
+	select {
+	case q.requests <- pending:
+	case <-ctx.Done():
+		return ApprovalDecision{}, ctx.Err()
+	}
+
+	select {
+	case decision := <-pending.decision:
+		return decision, nil
+	case <-ctx.Done():
+		return ApprovalDecision{}, ctx.Err()
+	}
+}
+
+// approve applies the approval policy of the tool. It returns the call to
+// execute, or the output to send back to the model when it was rejected.
+func (ae *AgentExecutor) approve(ctx context.Context, call ToolInvocation) (ToolInvocation, string, bool, error) {
+	switch ae.approvalPolicy(call.Name) {
+	case ApprovalNever:
+		return call, rejectionOutput("the tool is not allowed to run"), false, nil
+	case ApprovalAsk:
+		if ae.approver == nil {
+			return call, "", false, fmt.Errorf("tool %s requires approval but the executor has no approver", call.Name)
+		}
+
+		decision, err := ae.approver(ctx, call)
+		if err != nil {
+			return call, "", false, fmt.Errorf("failed to get approval for tool %s: %w", call.Name, err)
+		}
+		if !decision.Approved {
+			return call, rejectionOutput(decision.Reason), false, nil
+		}
+		if decision.Arguments != "" {
+			call.Arguments = decision.Arguments
+		}
+	}
+
+	return call, "", true, nil
+}
+
+// approvalPolicy prefers the policy set for the exact name over one whose
+// formatted name matches.
+func (ae *AgentExecutor) approvalPolicy(name string) ApprovalPolicy {
+	if policy, ok := ae.approvalPolicies[name]; ok {
+		return policy
+	}
+	for tool, policy := range ae.approvalPolicies {
+		if assistant.FormatString(tool) == name {
+			return policy
+		}
+	}
+	return ae.defaultApproval
+}
+
+// rejectionOutput tells the model the call did not run, so it can answer without it.
+func rejectionOutput(reason string) string {
+	if reason == "" {
+		reason = "rejected by a human reviewer"
+	}
+
+	output, _ := json.Marshal(map[string]string{"status": "rejected", "reason": reason})
+	return string(output)
+}
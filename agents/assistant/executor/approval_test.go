@@ -0,0 +1,80 @@
+package executor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/devalexandre/mylangchaingo/agents/assistant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/tools"
+)
+
+func TestApprovalQueue(t *testing.T) {
+	t.Parallel()
+
+	model := &scriptedModel{choices: []*llms.ContentChoice{
+		toolCallChoice("call_1", "Upper_Case", `{"__arg1":"draft"}`),
+		toolCallChoice("call_2", "Upper_Case", `{"__arg1":"secret"}`),
+		{Content: "done"},
+	}}
+	tool := &upperTool{}
+	queue := NewApprovalQueue()
+	le := NewLocalExecutor(model, &assistant.Assistant{},
+		WithTools([]tools.Tool{tool}),
+		WithToolApproval("Upper Case", ApprovalAsk),
+		WithApprover(queue.Approver),
+	)
+
+	go func() {
+		first := <-queue.Requests()
+		assert.Equal(t, `{"__arg1":"draft"}`, first.Call.Arguments)
+		first.Approve(`{"__arg1":"final"}`)
+
+		second := <-queue.Requests()
+		second.Reject("contains secrets")
+	}()
+
+	result, err := le.Invoke("go")
+	require.NoError(t, err)
+	assert.Equal(t, "done", result.Output)
+	assert.Equal(t, 1, tool.calls)
+
+	// the approved call ran with the modified arguments, the rejected one did not run.
+	history := le.Messages(result.ThreadID)
+	assert.Equal(t, "FINAL", history[2].Parts[0].(llms.ToolCallResponse).Content)
+	assert.JSONEq(t, `{"status":"rejected","reason":"contains secrets"}`, history[4].Parts[0].(llms.ToolCallResponse).Content)
+}
+
+func TestApprovalPolicies(t *testing.T) {
+	t.Parallel()
+
+	ae := NewAgentExecutor(&assistant.Assistant{},
+		WithTools([]tools.Tool{&upperTool{}}),
+		WithDefaultApproval(ApprovalNever),
+	)
+	output, err := ae.callTool(context.Background(), ToolInvocation{Name: "Upper_Case", Arguments: `{"__arg1":"x"}`})
+	require.NoError(t, err)
+	assert.Contains(t, output, "rejected")
+
+	ae = NewAgentExecutor(&assistant.Assistant{},
+		WithTools([]tools.Tool{&upperTool{}}),
+		WithToolApproval("Upper Case", ApprovalAsk),
+	)
+	_, err = ae.callTool(context.Background(), ToolInvocation{Name: "Upper_Case", Arguments: `{"__arg1":"x"}`})
+	require.Error(t, err)
+}
+
+func TestApprovalPolicyPrefersExactName(t *testing.T) {
+	t.Parallel()
+
+	ae := NewAgentExecutor(&assistant.Assistant{},
+		WithToolApproval("Upper Case", ApprovalAsk),
+		WithToolApproval("Upper_Case", ApprovalNever),
+	)
+	// map order is random, so a single lookup could pass by chance
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, ApprovalNever, ae.approvalPolicy("Upper_Case"))
+	}
+}
@@ -0,0 +1,108 @@
+package executor
+
+import (
+	"context"
+	"time"
+
+	"github.com/devalexandre/mylangchaingo/agents/assistant/runner"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// EventType is the kind of an Event.
+type EventType string
+
+const (
+	EventRunCreated    EventType = "run_created"
+	EventStatusChanged EventType = "status_changed"
+	EventToolStart     EventType = "tool_start"
+	EventToolEnd       EventType = "tool_end"
+	EventMessageDelta  EventType = "message_delta"
+	EventUsage         EventType = "usage"
+	EventRunCompleted  EventType = "run_completed"
+	EventRunFailed     EventType = "run_failed"
+)
+
+// Event reports the progress of a run. Only the fields of its type are set.
+type Event struct {
+	Type     EventType
+	Time     time.Time
+	ThreadID string
+	RunID    string
+
+	// Input is the input of the run, for EventRunCreated.
+	Input string
+	// Status is the new status of the run, for EventStatusChanged.
+	Status string
+	// Tool is the tool call, for EventToolStart and EventToolEnd.
+	Tool *ToolInvocation
+	// Output is the output of the tool for EventToolEnd, or the answer for EventRunCompleted.
+	Output string
+	// Text is the text added to the answer, for EventMessageDelta.
+	Text string
+	// Usage is the tokens used by the run, for EventUsage.
+	Usage *runner.Usage
+	// Err is the error of a tool for EventToolEnd, or of the run for EventRunFailed.
+	Err error
+}
+
+// emit sends the event to the channel and the callbacks handler of the
+// executor. The channel send blocks until it is read or ctx is done.
+func (ae *AgentExecutor) emit(ctx context.Context, event Event) {
+	if ae.events == nil && ae.callbacksHandler == nil {
+		return
+	}
+	event.Time = time.Now()
+
+	if handler := ae.callbacksHandler; handler != nil {
+		switch event.Type {
+		case EventRunCreated:
+			handler.HandleChainStart(ctx, map[string]any{
+				"input":     event.Input,
+				"thread_id": event.ThreadID,
+				"run_id":    event.RunID,
+			})
+		case EventToolStart:
+			handler.HandleAgentAction(ctx, schema.AgentAction{
+				Tool:      event.Tool.Name,
+				ToolInput: event.Tool.Arguments,
+				ToolID:    event.Tool.ToolCallID,
+			})
+			handler.HandleToolStart(ctx, event.Tool.Arguments)
+		case EventToolEnd:
+			if event.Err != nil {
+				handler.HandleToolError(ctx, event.Err)
+			} else {
+				handler.HandleToolEnd(ctx, event.Output)
+			}
+		case EventMessageDelta:
+			handler.HandleStreamingFunc(ctx, []byte(event.Text))
+		case EventRunCompleted:
+			handler.HandleAgentFinish(ctx, schema.AgentFinish{
+				ReturnValues: map[string]any{"output": event.Output},
+			})
+			handler.HandleChainEnd(ctx, map[string]any{
+				"output":    event.Output,
+				"thread_id": event.ThreadID,
+				"run_id":    event.RunID,
+			})
+		case EventRunFailed:
+			handler.HandleChainError(ctx, event.Err)
+		}
+	}
+
+	if ae.events != nil {
+		select {
+		case ae.events <- event:
+		case <-ctx.Done():
+		}
+	}
+}
+
+// emitEnd reports how the run ended.
+func (ae *AgentExecutor) emitEnd(ctx context.Context, result *Result, threadID, runID string, err error) {
+	if err != nil || result == nil {
+		ae.emit(ctx, Event{Type: EventRunFailed, ThreadID: threadID, RunID: runID, Err: err})
+		return
+	}
+	ae.emit(ctx, Event{Type: EventRunCompleted, ThreadID: threadID, RunID: runID, Output: result.Output})
+}
@@ -0,0 +1,240 @@
+package executor
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/devalexandre/mylangchaingo/agents/assistant"
+	"github.com/devalexandre/mylangchaingo/agents/assistant/runner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/tools"
+)
+
+type recordingHandler struct {
+	callbacks.SimpleHandler
+	calls []string
+}
+
+func (h *recordingHandler) HandleChainStart(context.Context, map[string]any) {
+	h.calls = append(h.calls, "chain_start")
+}
+
+func (h *recordingHandler) HandleToolStart(_ context.Context, input string) {
+	h.calls = append(h.calls, "tool_start "+input)
+}
+
+func (h *recordingHandler) HandleToolEnd(_ context.Context, output string) {
+	h.calls = append(h.calls, "tool_end "+output)
+}
+
+func (h *recordingHandler) HandleChainEnd(_ context.Context, outputs map[string]any) {
+	h.calls = append(h.calls, "chain_end "+outputs["output"].(string))
+}
+
+func TestLocalExecutorEvents(t *testing.T) {
+	t.Parallel()
+
+	call := toolCallChoice("call_1", "Upper_Case", `{"__arg1":"hi"}`)
+	call.GenerationInfo = map[string]any{"TotalTokens": 10}
+	answer := &llms.ContentChoice{Content: "HI", GenerationInfo: map[string]any{"TotalTokens": 5}}
+	model := &scriptedModel{choices: []*llms.ContentChoice{call, answer}}
+
+	events := make(chan Event, 32)
+	handler := &recordingHandler{}
+	le := NewLocalExecutor(model, &assistant.Assistant{},
+		WithTools([]tools.Tool{&upperTool{}}),
+		WithEvents(events),
+		WithCallbacksHandler(handler),
+	)
+
+	result, err := le.Invoke("hi")
+	require.NoError(t, err)
+	close(events)
+
+	var types []EventType
+	var statuses []string
+	var text string
+	for event := range events {
+		assert.Equal(t, result.ThreadID, event.ThreadID)
+		assert.Equal(t, result.RunID, event.RunID)
+		assert.False(t, event.Time.IsZero())
+		types = append(types, event.Type)
+
+		switch event.Type {
+		case EventStatusChanged:
+			statuses = append(statuses, event.Status)
+		case EventToolEnd:
+			assert.Equal(t, "Upper_Case", event.Tool.Name)
+			assert.Equal(t, "HI", event.Output)
+		case EventMessageDelta:
+			text += event.Text
+		case EventUsage:
+			assert.Equal(t, 15, event.Usage.TotalTokens)
+		}
+	}
+
+	assert.Equal(t, []EventType{
+		EventRunCreated,
+		EventStatusChanged,
+		EventStatusChanged,
+		EventToolStart,
+		EventToolEnd,
+		EventStatusChanged,
+		EventMessageDelta,
+		EventMessageDelta,
+		EventStatusChanged,
+		EventUsage,
+		EventRunCompleted,
+	}, types)
+	assert.Equal(t, []string{
+		runner.StatusInProgress,
+		runner.StatusRequiresAction,
+		runner.StatusInProgress,
+		runner.StatusCompleted,
+	}, statuses)
+	assert.Equal(t, "HI", text)
+
+	assert.Equal(t, []string{
+		"chain_start",
+		`tool_start {"__arg1":"hi"}`,
+		"tool_end HI",
+		"chain_end HI",
+	}, handler.calls)
+}
+
+func TestEventsFailedRun(t *testing.T) {
+	t.Parallel()
+
+	model := &scriptedModel{}
+	events := make(chan Event, 8)
+	le := NewLocalExecutor(model, &assistant.Assistant{}, WithEvents(events))
+
+	_, err := le.Invoke("hi")
+	require.Error(t, err)
+	close(events)
+
+	var last Event
+	for event := range events {
+		last = event
+	}
+	assert.Equal(t, EventRunFailed, last.Type)
+	assert.Equal(t, err, last.Err)
+	assert.NotEmpty(t, last.RunID)
+}
+
+func TestAgentExecutorStreamsEvents(t *testing.T) {
+	var submitted string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/threads/thread_1/messages" && r.Method == http.MethodPost:
+			_, _ = io.WriteString(w, `{"id":"msg_1"}`)
+		case r.URL.Path == "/threads/thread_1/runs" && r.Method == http.MethodPost:
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = io.WriteString(w, "event: thread.run.created\ndata: {\"id\":\"run_1\",\"status\":\"queued\"}\n\n"+
+				"event: thread.run.in_progress\ndata: {\"id\":\"run_1\",\"status\":\"in_progress\"}\n\n"+
+				"event: thread.run.step.completed\ndata: {\"id\":\"step_1\",\"usage\":{\"total_tokens\":10}}\n\n"+
+				"event: thread.run.requires_action\ndata: {\"id\":\"run_1\",\"status\":\"requires_action\",\"required_action\":{\"type\":\"submit_tool_outputs\","+
+				"\"submit_tool_outputs\":{\"tool_calls\":[{\"id\":\"call_1\",\"type\":\"function\",\"function\":{\"name\":\"Upper_Case\",\"arguments\":\"{\\\"__arg1\\\":\\\"hi\\\"}\"}}]}}}\n\n"+
+				"event: done\ndata: [DONE]\n\n")
+		case r.URL.Path == "/threads/thread_1/runs/run_1/submit_tool_outputs":
+			body, _ := io.ReadAll(r.Body)
+			submitted = string(body)
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = io.WriteString(w, "event: thread.run.in_progress\ndata: {\"id\":\"run_1\",\"status\":\"in_progress\"}\n\n"+
+				"event: thread.message.delta\ndata: {\"delta\":{\"content\":[{\"type\":\"text\",\"text\":{\"value\":\"It is \"}}]}}\n\n"+
+				"event: thread.message.delta\ndata: {\"delta\":{\"content\":[{\"type\":\"text\",\"text\":{\"value\":\"HI\"}}]}}\n\n"+
+				"event: thread.run.completed\ndata: {\"id\":\"run_1\",\"status\":\"completed\",\"usage\":{\"total_tokens\":25}}\n\n"+
+				"event: done\ndata: [DONE]\n\n")
+		case r.URL.Path == "/threads/thread_1/messages":
+			_, _ = io.WriteString(w, `{"data":[{"id":"msg_2","role":"assistant","content":"It is HI"}]}`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	baseURL := assistant.BaseURL
+	assistant.BaseURL = srv.URL
+	defer func() { assistant.BaseURL = baseURL }()
+
+	events := make(chan Event, 32)
+	ae := NewAgentExecutor(&assistant.Assistant{ID: "asst_1"},
+		WithTools([]tools.Tool{&upperTool{}}),
+		WithEvents(events),
+	)
+
+	result, err := ae.ResumeSession("thread_1").Send(context.Background(), "hi")
+	require.NoError(t, err)
+	close(events)
+	assert.Equal(t, "It is HI", result.Output)
+	assert.Equal(t, 25, result.Usage.TotalTokens)
+	assert.Contains(t, submitted, `"output":"HI"`)
+
+	var types []EventType
+	var deltas []string
+	for event := range events {
+		assert.Equal(t, "run_1", event.RunID)
+		types = append(types, event.Type)
+		if event.Type == EventMessageDelta {
+			deltas = append(deltas, event.Text)
+		}
+	}
+	assert.Equal(t, []EventType{
+		EventRunCreated,
+		EventStatusChanged,
+		EventStatusChanged,
+		EventStatusChanged,
+		EventToolStart,
+		EventToolEnd,
+		EventStatusChanged,
+		EventMessageDelta,
+		EventMessageDelta,
+		EventStatusChanged,
+		EventUsage,
+		EventRunCompleted,
+	}, types)
+	assert.Equal(t, []string{"It is ", "HI"}, deltas)
+}
+
+func TestAgentExecutorFailedRunEvent(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/threads/thread_1/messages" && r.Method == http.MethodPost:
+			_, _ = io.WriteString(w, `{"id":"msg_1"}`)
+		case r.URL.Path == "/threads/thread_1/runs" && r.Method == http.MethodPost:
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = io.WriteString(w, "event: thread.run.created\ndata: {\"id\":\"run_1\",\"status\":\"queued\"}\n\n"+
+				"event: thread.run.failed\ndata: {\"id\":\"run_1\",\"status\":\"failed\",\"last_error\":{\"code\":\"server_error\",\"message\":\"boom\"}}\n\n"+
+				"event: done\ndata: [DONE]\n\n")
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	baseURL := assistant.BaseURL
+	assistant.BaseURL = srv.URL
+	defer func() { assistant.BaseURL = baseURL }()
+
+	events := make(chan Event, 8)
+	ae := NewAgentExecutor(&assistant.Assistant{ID: "asst_1"}, WithEvents(events))
+
+	_, err := ae.ResumeSession("thread_1").Send(context.Background(), "hi")
+	require.ErrorContains(t, err, "server_error: boom")
+	close(events)
+
+	var last Event
+	for event := range events {
+		last = event
+	}
+	assert.Equal(t, EventRunFailed, last.Type)
+	assert.Equal(t, "run_1", last.RunID)
+}
@@ -0,0 +1,65 @@
+package executor
+
+import (
+	"github.com/devalexandre/mylangchaingo/agents/assistant"
+	"github.com/devalexandre/mylangchaingo/agents/assistant/file"
+	"github.com/devalexandre/mylangchaingo/agents/assistant/message"
+	"github.com/devalexandre/mylangchaingo/agents/assistant/runner"
+)
+
+// DownloadFiles saves the files generated by the run in dir and returns their
+// paths. Each file is saved once, and files already in dir are kept.
+func (r *Result) DownloadFiles(dir string) ([]string, error) {
+	paths := make([]string, 0, len(r.Files))
+	seen := map[string]bool{}
+	for _, generated := range r.Files {
+		if seen[generated.FileID] {
+			continue
+		}
+		seen[generated.FileID] = true
+
+		path, err := file.DownloadFileToDir(generated.FileID, dir)
+		if err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// appendStepFiles adds the images output by the code interpreter in the steps
+// that are not already in files.
+func appendStepFiles(files []message.GeneratedFile, steps []runner.RunStep) []message.GeneratedFile {
+	seen := map[string]bool{}
+	for _, generated := range files {
+		seen[generated.FileID] = true
+	}
+
+	for _, step := range steps {
+		for _, call := range step.StepDetails.ToolCalls {
+			if call.CodeInterpreter == nil {
+				continue
+			}
+			for _, output := range call.CodeInterpreter.Outputs {
+				if output.Image == nil || seen[output.Image.FileID] {
+					continue
+				}
+				seen[output.Image.FileID] = true
+				files = append(files, message.GeneratedFile{FileID: output.Image.FileID, Image: true})
+			}
+		}
+	}
+	return files
+}
+
+func usesCodeInterpreter(agent *assistant.Assistant) bool {
+	if agent == nil || agent.Tools == nil {
+		return false
+	}
+	for _, tool := range *agent.Tools {
+		if tool.Type == string(assistant.CodeInterpreterType) {
+			return true
+		}
+	}
+	return false
+}
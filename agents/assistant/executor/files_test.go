@@ -0,0 +1,81 @@
+package executor
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/devalexandre/mylangchaingo/agents/assistant"
+	"github.com/devalexandre/mylangchaingo/agents/assistant/message"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+func TestResultFiles(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/threads/thread_1/messages":
+			if r.Method == http.MethodPost {
+				_, _ = io.WriteString(w, `{"id":"msg_1"}`)
+				return
+			}
+			_, _ = io.WriteString(w, `{"data":[{"id":"msg_2","role":"assistant","content":[
+				{"type":"image_file","image_file":{"file_id":"file-chart"}},
+				{"type":"text","text":{"value":"Download sandbox:/mnt/data/sales.csv","annotations":[
+					{"type":"file_path","text":"sandbox:/mnt/data/sales.csv","start_index":9,"end_index":37,"file_path":{"file_id":"file-csv"}}]}}]}]}`)
+		case "/threads/thread_1/runs":
+			_, _ = io.WriteString(w, `{"id":"run_1","status":"queued"}`)
+		case "/threads/thread_1/runs/run_1":
+			_, _ = io.WriteString(w, `{"id":"run_1","status":"completed"}`)
+		case "/threads/thread_1/runs/run_1/steps":
+			_, _ = io.WriteString(w, `{"data":[{"id":"step_1","type":"tool_calls","status":"completed","step_details":{"type":"tool_calls",
+				"tool_calls":[{"id":"call_1","type":"code_interpreter","code_interpreter":{"input":"plot()","outputs":[
+					{"type":"image","image":{"file_id":"file-chart"}},{"type":"image","image":{"file_id":"file-hist"}}]}}]}}]}`)
+		case "/files/file-csv":
+			_, _ = io.WriteString(w, `{"id":"file-csv","filename":"/mnt/data/sales.csv"}`)
+		case "/files/file-csv/content":
+			_, _ = io.WriteString(w, "month,total\njan,10\n")
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	baseURL := assistant.BaseURL
+	assistant.BaseURL = srv.URL
+	defer func() { assistant.BaseURL = baseURL }()
+
+	agent := &assistant.Assistant{ID: "asst_1", Tools: &[]llms.Tool{{Type: string(assistant.CodeInterpreterType)}}}
+	ae := NewAgentExecutor(agent, WithPollInterval(time.Millisecond))
+
+	result, err := ae.ResumeSession("thread_1").Send(context.Background(), "plot the sales")
+	require.NoError(t, err)
+	assert.Equal(t, []message.GeneratedFile{
+		{FileID: "file-chart", Image: true},
+		{FileID: "file-csv", Path: "sandbox:/mnt/data/sales.csv"},
+		{FileID: "file-hist", Image: true},
+	}, result.Files)
+	assert.Nil(t, result.Steps)
+
+	dir := t.TempDir()
+	// a file referenced twice is saved once
+	result.Files = []message.GeneratedFile{result.Files[1], result.Files[1]}
+	paths, err := result.DownloadFiles(dir)
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join(dir, "sales.csv")}, paths)
+
+	content, err := os.ReadFile(paths[0])
+	require.NoError(t, err)
+	assert.Equal(t, "month,total\njan,10\n", string(content))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
@@ -0,0 +1,134 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrLimitExceeded is wrapped by the LimitError returned when a run is stopped by its Limits.
+var ErrLimitExceeded = errors.New("executor limit exceeded")
+
+// Limit names the safeguard that stopped a run.
+type Limit string
+
+const (
+	LimitRounds       Limit = "rounds"
+	LimitToolCalls    Limit = "tool_calls"
+	LimitDuration     Limit = "duration"
+	LimitTokens       Limit = "tokens"
+	LimitRepeatedCall Limit = "repeated_call"
+)
+
+// Limits are the safeguards of a run. Zero values are unlimited.
+type Limits struct {
+	// MaxRounds is how many times the run can ask for tool outputs.
+	MaxRounds int
+	// MaxToolCalls is how many tools the run can call in total.
+	MaxToolCalls int
+	// MaxDuration is how long the run can take.
+	MaxDuration time.Duration
+	// MaxTokens is how many tokens the run can use. While a hosted run is in
+	// progress only its finished steps are counted.
+	MaxTokens int
+	// MaxRepeatedCalls is how many times the same tool can be called with
+	// the same arguments.
+	MaxRepeatedCalls int
+}
+
+// LimitError is returned, along with a Result holding the partial answer,
+// when a run is stopped by its Limits. The remote run is cancelled.
+type LimitError struct {
+	Limit Limit
+	// Max is the limit that was exceeded, in nanoseconds for LimitDuration.
+	Max int64
+	// Call is the tool call that hit the limit, for the tool call limits.
+	Call *ToolInvocation
+}
+
+func (e *LimitError) Error() string {
+	max := fmt.Sprint(e.Max)
+	if e.Limit == LimitDuration {
+		max = time.Duration(e.Max).String()
+	}
+
+	if e.Call != nil {
+		return fmt.Sprintf("%v: %s (max %s) at tool %s", ErrLimitExceeded, e.Limit, max, e.Call.Name)
+	}
+	return fmt.Sprintf("%v: %s (max %s)", ErrLimitExceeded, e.Limit, max)
+}
+
+func (e *LimitError) Unwrap() error {
+	return ErrLimitExceeded
+}
+
+// runBudget tracks what a run has used against the Limits of the executor.
+// A nil budget has no limits.
+type runBudget struct {
+	limits    Limits
+	started   time.Time
+	rounds    int
+	toolCalls int
+	tokens    int
+	calls     map[string]int
+}
+
+func (ae *AgentExecutor) newBudget() *runBudget {
+	return &runBudget{limits: ae.limits, started: time.Now(), calls: map[string]int{}}
+}
+
+// round is called each time the run asks for tool outputs.
+func (b *runBudget) round() error {
+	if b == nil {
+		return nil
+	}
+
+	b.rounds++
+	if b.limits.MaxRounds > 0 && b.rounds > b.limits.MaxRounds {
+		return &LimitError{Limit: LimitRounds, Max: int64(b.limits.MaxRounds)}
+	}
+	return b.elapsed()
+}
+
+// toolCall is called before each tool is executed.
+func (b *runBudget) toolCall(call ToolInvocation) error {
+	if b == nil {
+		return nil
+	}
+
+	b.toolCalls++
+	if b.limits.MaxToolCalls > 0 && b.toolCalls > b.limits.MaxToolCalls {
+		return &LimitError{Limit: LimitToolCalls, Max: int64(b.limits.MaxToolCalls), Call: &call}
+	}
+
+	key := call.Name + "\x00" + call.Arguments
+	b.calls[key]++
+	if b.limits.MaxRepeatedCalls > 0 && b.calls[key] > b.limits.MaxRepeatedCalls {
+		return &LimitError{Limit: LimitRepeatedCall, Max: int64(b.limits.MaxRepeatedCalls), Call: &call}
+	}
+	return nil
+}
+
+// usage records the tokens used so far by the run.
+func (b *runBudget) usage(tokens int) error {
+	if b == nil {
+		return nil
+	}
+
+	b.tokens = tokens
+	if b.limits.MaxTokens > 0 && b.tokens > b.limits.MaxTokens {
+		return &LimitError{Limit: LimitTokens, Max: int64(b.limits.MaxTokens)}
+	}
+	return nil
+}
+
+func (b *runBudget) elapsed() error {
+	if b == nil {
+		return nil
+	}
+
+	if b.limits.MaxDuration > 0 && time.Since(b.started) > b.limits.MaxDuration {
+		return &LimitError{Limit: LimitDuration, Max: int64(b.limits.MaxDuration)}
+	}
+	return nil
+}
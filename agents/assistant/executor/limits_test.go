@@ -0,0 +1,183 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/devalexandre/mylangchaingo/agents/assistant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/tools"
+)
+
+func TestLocalExecutorRepeatedCalls(t *testing.T) {
+	t.Parallel()
+
+	loop := toolCallChoice("call", "Upper_Case", `{"__arg1":"again"}`)
+	loop.Content = "Let me check."
+	model := &scriptedModel{choices: []*llms.ContentChoice{loop, loop, loop, {Content: "never reached"}}}
+	tool := &upperTool{}
+	le := NewLocalExecutor(model, &assistant.Assistant{},
+		WithTools([]tools.Tool{tool}),
+		WithLimits(Limits{MaxRepeatedCalls: 2}),
+	)
+
+	result, err := le.Invoke("go")
+	require.ErrorIs(t, err, ErrLimitExceeded)
+	var limitErr *LimitError
+	require.ErrorAs(t, err, &limitErr)
+	assert.Equal(t, LimitRepeatedCall, limitErr.Limit)
+	assert.Equal(t, "Upper_Case", limitErr.Call.Name)
+	assert.Equal(t, 2, tool.calls)
+
+	require.NotNil(t, result)
+	assert.Equal(t, "Let me check.\nLet me check.\nLet me check.", result.Output)
+
+	// Run returns the partial answer along with the error.
+	model.choices = []*llms.ContentChoice{loop, loop, loop}
+	output, err := le.Run("go")
+	require.ErrorIs(t, err, ErrLimitExceeded)
+	assert.Equal(t, "Let me check.\nLet me check.\nLet me check.", output)
+
+	// the stopped round is not kept, so the thread can continue.
+	history := le.Messages(result.ThreadID)
+	assert.Equal(t, llms.ChatMessageTypeAI, history[len(history)-1].Role)
+	assert.Len(t, history, 6)
+}
+
+func TestAgentExecutorMaxRounds(t *testing.T) {
+	var cancelled bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/threads/thread_1/messages" && r.Method == http.MethodPost:
+			_, _ = io.WriteString(w, `{"id":"msg_1"}`)
+		case r.URL.Path == "/threads/thread_1/runs" && r.Method == http.MethodPost:
+			_, _ = io.WriteString(w, `{"id":"run_1","status":"queued"}`)
+		case r.URL.Path == "/threads/thread_1/runs/run_1":
+			// the run asks for the same tool forever
+			_, _ = io.WriteString(w, `{"id":"run_1","status":"requires_action","required_action":{"type":"submit_tool_outputs",
+				"submit_tool_outputs":{"tool_calls":[{"id":"call_1","type":"function","function":{"name":"Upper_Case","arguments":"{\"__arg1\":\"x\"}"}}]}}}`)
+		case r.URL.Path == "/threads/thread_1/runs/run_1/submit_tool_outputs":
+			_, _ = io.WriteString(w, `{"id":"run_1","status":"queued"}`)
+		case r.URL.Path == "/threads/thread_1/runs/run_1/cancel":
+			cancelled = true
+			_, _ = io.WriteString(w, `{"id":"run_1","status":"cancelling"}`)
+		case r.URL.Path == "/threads/thread_1/messages":
+			_, _ = io.WriteString(w, `{"data":[{"id":"msg_2","role":"assistant","content":"Working on it"}]}`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	baseURL := assistant.BaseURL
+	assistant.BaseURL = srv.URL
+	defer func() { assistant.BaseURL = baseURL }()
+
+	tool := &upperTool{}
+	ae := NewAgentExecutor(&assistant.Assistant{ID: "asst_1"},
+		WithTools([]tools.Tool{tool}),
+		WithPollInterval(time.Millisecond),
+		WithLimits(Limits{MaxRounds: 3}),
+	)
+
+	result, err := ae.ResumeSession("thread_1").Send(context.Background(), "go")
+	var limitErr *LimitError
+	require.ErrorAs(t, err, &limitErr)
+	assert.Equal(t, LimitRounds, limitErr.Limit)
+	assert.Equal(t, fmt.Sprintf("%v: rounds (max 3)", ErrLimitExceeded), err.Error())
+	assert.Equal(t, 3, tool.calls)
+	assert.True(t, cancelled)
+	require.NotNil(t, result)
+	assert.Equal(t, "Working on it", result.Output)
+}
+
+func TestAgentExecutorMaxTokens(t *testing.T) {
+	var cancelled bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/threads/thread_1/messages" && r.Method == http.MethodPost:
+			_, _ = io.WriteString(w, `{"id":"msg_1"}`)
+		case r.URL.Path == "/threads/thread_1/runs" && r.Method == http.MethodPost:
+			_, _ = io.WriteString(w, `{"id":"run_1","status":"queued"}`)
+		case r.URL.Path == "/threads/thread_1/runs/run_1":
+			// the run has no usage until it ends
+			_, _ = io.WriteString(w, `{"id":"run_1","status":"in_progress"}`)
+		case r.URL.Path == "/threads/thread_1/runs/run_1/steps":
+			_, _ = io.WriteString(w, `{"data":[{"id":"step_1","status":"completed","usage":{"total_tokens":80}},
+				{"id":"step_2","status":"completed","usage":{"total_tokens":70}},{"id":"step_3","status":"in_progress"}]}`)
+		case r.URL.Path == "/threads/thread_1/runs/run_1/cancel":
+			cancelled = true
+			_, _ = io.WriteString(w, `{"id":"run_1","status":"cancelling"}`)
+		case r.URL.Path == "/threads/thread_1/messages":
+			_, _ = io.WriteString(w, `{"data":[]}`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	baseURL := assistant.BaseURL
+	assistant.BaseURL = srv.URL
+	defer func() { assistant.BaseURL = baseURL }()
+
+	ae := NewAgentExecutor(&assistant.Assistant{ID: "asst_1"},
+		WithPollInterval(time.Millisecond),
+		WithLimits(Limits{MaxTokens: 100}),
+	)
+
+	_, err := ae.ResumeSession("thread_1").Send(context.Background(), "go")
+	var limitErr *LimitError
+	require.ErrorAs(t, err, &limitErr)
+	assert.Equal(t, LimitTokens, limitErr.Limit)
+	assert.True(t, cancelled)
+}
+
+func TestAgentExecutorStreamStoppedBeforeRunCreated(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/threads/thread_1/messages" && r.Method == http.MethodPost:
+			_, _ = io.WriteString(w, `{"id":"msg_1"}`)
+		case r.URL.Path == "/threads/thread_1/runs" && r.Method == http.MethodPost:
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = io.WriteString(w, "event: thread.created\ndata: {\"id\":\"thread_1\"}\n\n"+
+				"event: thread.run.created\ndata: {\"id\":\"run_1\",\"status\":\"queued\"}\n\n")
+		default:
+			// the run has no ID yet, so there is nothing to cancel
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	baseURL := assistant.BaseURL
+	assistant.BaseURL = srv.URL
+	defer func() { assistant.BaseURL = baseURL }()
+
+	events := make(chan Event, 8)
+	ae := NewAgentExecutor(&assistant.Assistant{ID: "asst_1"},
+		WithEvents(events),
+		WithLimits(Limits{MaxDuration: time.Nanosecond}),
+	)
+
+	result, err := ae.ResumeSession("thread_1").Send(context.Background(), "go")
+	var limitErr *LimitError
+	require.ErrorAs(t, err, &limitErr)
+	assert.Equal(t, LimitDuration, limitErr.Limit)
+	assert.Nil(t, result)
+	close(events)
+
+	var last Event
+	for event := range events {
+		last = event
+	}
+	assert.Equal(t, EventRunFailed, last.Type)
+}
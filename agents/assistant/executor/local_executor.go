@@ -0,0 +1,305 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/devalexandre/mylangchaingo/agents/assistant"
+	"github.com/devalexandre/mylangchaingo/agents/assistant/runner"
+	"github.com/google/uuid"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// LocalExecutor runs the same thread, tools and run loop as the AgentExecutor,
+// but locally against any llms.Model using its native tool calling. Threads
+// are kept in memory.
+//
+// The instructions, temperature and top_p of the agent are used, its model is
+// not: the llms.Model is called with the model it was created with.
+type LocalExecutor struct {
+	Model llms.Model
+
+	config  *AgentExecutor
+	mu      sync.Mutex
+	threads map[string][]llms.MessageContent
+}
+
+// NewLocalExecutor creates an executor that runs the agent with model. It
+// accepts the same options as NewAgentExecutor.
+func NewLocalExecutor(model llms.Model, agent *assistant.Assistant, opts ...ExecutorOption) *LocalExecutor {
+	config := &AgentExecutor{
+		Agent: agent,
+	}
+
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	return &LocalExecutor{
+		Model:   model,
+		config:  config,
+		threads: map[string][]llms.MessageContent{},
+	}
+}
+
+// Run executes the agent with the provided input and returns the response
+func (le *LocalExecutor) Run(input string) (string, error) {
+	result, err := le.Invoke(input)
+	if err != nil {
+		// a run stopped by its limits still has a partial answer
+		if result != nil {
+			return result.Output, err
+		}
+		return "", err
+	}
+
+	return result.Output, nil
+}
+
+// Invoke executes the agent with the provided input in a new thread.
+func (le *LocalExecutor) Invoke(input string) (*Result, error) {
+	session, err := le.NewSession()
+	if err != nil {
+		return nil, err
+	}
+
+	return session.Send(context.Background(), input)
+}
+
+// NewSession starts a conversation in a new thread.
+func (le *LocalExecutor) NewSession() (*Session, error) {
+	threadID := "thread_" + uuid.New().String()
+
+	le.mu.Lock()
+	le.threads[threadID] = nil
+	le.mu.Unlock()
+
+	return le.ResumeSession(threadID), nil
+}
+
+// ResumeSession continues the conversation of a thread of this executor.
+// Unknown threads start empty.
+func (le *LocalExecutor) ResumeSession(threadID string) *Session {
+	return &Session{ThreadID: threadID, executor: le.config, backend: le}
+}
+
+// Messages returns the messages of a thread, without the instructions.
+func (le *LocalExecutor) Messages(threadID string) []llms.MessageContent {
+	le.mu.Lock()
+	defer le.mu.Unlock()
+
+	return append([]llms.MessageContent(nil), le.threads[threadID]...)
+}
+
+// DeleteThread forgets the messages of a thread.
+func (le *LocalExecutor) DeleteThread(threadID string) {
+	le.mu.Lock()
+	defer le.mu.Unlock()
+
+	delete(le.threads, threadID)
+}
+
+func (le *LocalExecutor) send(ctx context.Context, threadID, input string) (*Result, error) {
+	history := append(le.Messages(threadID), llms.TextParts(llms.ChatMessageTypeHuman, input))
+	result := &Result{
+		ThreadID: threadID,
+		RunID:    "run_" + uuid.New().String(),
+	}
+	// the run is only identified when it failed
+	failed := &Result{ThreadID: threadID, RunID: result.RunID}
+
+	ae := le.config
+	ae.emit(ctx, Event{Type: EventRunCreated, ThreadID: threadID, RunID: result.RunID, Input: input})
+	ae.emit(ctx, Event{Type: EventStatusChanged, ThreadID: threadID, RunID: result.RunID, Status: runner.StatusInProgress})
+
+	opts := le.callOptions()
+	if ae.streams() {
+		// the call options of the executor come last, so they can replace it
+		opts = append([]llms.CallOption{llms.WithStreamingFunc(func(ctx context.Context, chunk []byte) error {
+			if len(chunk) > 0 && !isToolCallChunk(chunk) {
+				ae.emit(ctx, Event{Type: EventMessageDelta, ThreadID: threadID, RunID: result.RunID, Text: string(chunk)})
+			}
+			return nil
+		})}, opts...)
+	}
+	budget := le.config.newBudget()
+	tokens := 0
+	var partial []string
+	var stop error
+	for stop == nil {
+		if stop = budget.elapsed(); stop != nil {
+			break
+		}
+
+		resp, err := le.Model.GenerateContent(ctx, le.withInstructions(history), opts...)
+		if err != nil {
+			return failed, fmt.Errorf("failed to generate content: %w", err)
+		}
+		if len(resp.Choices) == 0 {
+			return failed, errors.New("empty response from model")
+		}
+		choice := resp.Choices[0]
+
+		tokens += totalTokens(choice.GenerationInfo)
+		if len(choice.ToolCalls) == 0 {
+			history = append(history, llms.TextParts(llms.ChatMessageTypeAI, choice.Content))
+			result.Output = choice.Content
+			result.Steps = le.appendStep(result.Steps, runner.StepDetails{Type: runner.RunStepTypeMessageCreation})
+			break
+		}
+
+		if choice.Content != "" {
+			partial = append(partial, choice.Content)
+		}
+		if stop = budget.usage(tokens); stop != nil {
+			break
+		}
+		if stop = budget.round(); stop != nil {
+			break
+		}
+		ae.emit(ctx, Event{Type: EventStatusChanged, ThreadID: threadID, RunID: result.RunID, Status: runner.StatusRequiresAction})
+
+		// the message with the calls has to come before the answers of the tools
+		call := llms.MessageContent{Role: llms.ChatMessageTypeAI}
+		if choice.Content != "" {
+			call.Parts = append(call.Parts, llms.TextContent{Text: choice.Content})
+		}
+		for _, toolCall := range choice.ToolCalls {
+			call.Parts = append(call.Parts, toolCall)
+		}
+		// the round is only added to the history once all its tools answered
+		round := []llms.MessageContent{call}
+
+		details := runner.StepDetails{Type: runner.RunStepTypeToolCalls}
+		for _, toolCall := range choice.ToolCalls {
+			if toolCall.FunctionCall == nil {
+				return failed, fmt.Errorf("tool call %s has no function", toolCall.ID)
+			}
+
+			invocation := ToolInvocation{
+				ThreadID:   threadID,
+				RunID:      result.RunID,
+				ToolCallID: toolCall.ID,
+				Name:       toolCall.FunctionCall.Name,
+				Arguments:  toolCall.FunctionCall.Arguments,
+			}
+			if stop = budget.toolCall(invocation); stop != nil {
+				break
+			}
+
+			output, err := le.config.callTool(ctx, invocation)
+			if err != nil {
+				return failed, fmt.Errorf("failed to handle tools execution: %w", err)
+			}
+
+			round = append(round, llms.MessageContent{
+				Role: llms.ChatMessageTypeTool,
+				Parts: []llms.ContentPart{llms.ToolCallResponse{
+					ToolCallID: toolCall.ID,
+					Name:       toolCall.FunctionCall.Name,
+					Content:    output,
+				}},
+			})
+			details.ToolCalls = append(details.ToolCalls, runner.StepToolCall{
+				ID:   toolCall.ID,
+				Type: assistant.ToolTypeFunction,
+				Function: &runner.FunctionCall{
+					Name:      toolCall.FunctionCall.Name,
+					Arguments: toolCall.FunctionCall.Arguments,
+					Output:    &output,
+				},
+			})
+		}
+		if stop == nil {
+			history = append(history, round...)
+			result.Steps = le.appendStep(result.Steps, details)
+			ae.emit(ctx, Event{Type: EventStatusChanged, ThreadID: threadID, RunID: result.RunID, Status: runner.StatusInProgress})
+		}
+	}
+
+	if stop != nil {
+		// stopped by the limits: the partial answer stays in the history
+		result.Output = strings.Join(partial, "\n")
+		if result.Output != "" {
+			history = append(history, llms.TextParts(llms.ChatMessageTypeAI, result.Output))
+		}
+	}
+
+	le.mu.Lock()
+	le.threads[threadID] = history
+	le.mu.Unlock()
+
+	if tokens > 0 {
+		result.Usage = &runner.Usage{TotalTokens: tokens}
+	}
+	if stop == nil {
+		ae.emit(ctx, Event{Type: EventStatusChanged, ThreadID: threadID, RunID: result.RunID, Status: runner.StatusCompleted})
+	}
+	if !le.config.stepTrace {
+		result.Steps = nil
+	}
+	return result, stop
+}
+
+// isToolCallChunk reports whether a streamed chunk is the JSON of the tool
+// calls requested so far, which the models stream along with the text.
+func isToolCallChunk(chunk []byte) bool {
+	return chunk[0] == '[' && json.Valid(chunk)
+}
+
+// totalTokens reads the token usage reported by the openai and maritaca models.
+func totalTokens(info map[string]any) int {
+	switch tokens := info["TotalTokens"].(type) {
+	case int:
+		return tokens
+	case int64:
+		return int(tokens)
+	case float64:
+		return int(tokens)
+	}
+	return 0
+}
+
+// appendStep records a completed step, mirroring the run steps of the Assistants API.
+func (le *LocalExecutor) appendStep(steps []runner.RunStep, details runner.StepDetails) []runner.RunStep {
+	return append(steps, runner.RunStep{
+		ID:          "step_" + uuid.New().String(),
+		Object:      "thread.run.step",
+		Type:        details.Type,
+		Status:      runner.StatusCompleted,
+		StepDetails: details,
+	})
+}
+
+func (le *LocalExecutor) withInstructions(history []llms.MessageContent) []llms.MessageContent {
+	agent := le.config.Agent
+	if agent == nil || agent.Instructions == "" {
+		return history
+	}
+
+	return append([]llms.MessageContent{llms.TextParts(llms.ChatMessageTypeSystem, agent.Instructions)}, history...)
+}
+
+func (le *LocalExecutor) callOptions() []llms.CallOption {
+	var opts []llms.CallOption
+	if agent := le.config.Agent; agent != nil {
+		if agent.Temperature != nil {
+			opts = append(opts, llms.WithTemperature(*agent.Temperature))
+		}
+		if agent.TopP != nil {
+			opts = append(opts, llms.WithTopP(*agent.TopP))
+		}
+	}
+
+	if len(le.config.Tools) > 0 {
+		definitions := &assistant.Assistant{}
+		assistant.WithTools(le.config.Tools)(definitions)
+		opts = append(opts, llms.WithTools(*definitions.Tools))
+	}
+
+	return append(opts, le.config.callOptions...)
+}
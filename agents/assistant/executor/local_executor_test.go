@@ -0,0 +1,116 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/devalexandre/mylangchaingo/agents/assistant"
+	"github.com/devalexandre/mylangchaingo/agents/assistant/runner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/tools"
+)
+
+// scriptedModel answers with its choices in order and records what it was sent.
+type scriptedModel struct {
+	choices  []*llms.ContentChoice
+	calls    [][]llms.MessageContent
+	toolsSet []bool
+}
+
+func (m *scriptedModel) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	opts := llms.CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	m.calls = append(m.calls, messages)
+	m.toolsSet = append(m.toolsSet, len(opts.Tools) > 0)
+
+	if len(m.choices) == 0 {
+		return nil, errors.New("no more choices")
+	}
+	choice := m.choices[0]
+	m.choices = m.choices[1:]
+	if opts.StreamingFunc != nil {
+		// stream the text in two chunks, and the tool calls as JSON like the openai models
+		for _, chunk := range []string{choice.Content[:len(choice.Content)/2], choice.Content[len(choice.Content)/2:]} {
+			_ = opts.StreamingFunc(ctx, []byte(chunk))
+		}
+		if len(choice.ToolCalls) > 0 {
+			calls, _ := json.Marshal(choice.ToolCalls)
+			_ = opts.StreamingFunc(ctx, calls)
+		}
+	}
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{choice}}, nil
+}
+
+func (m *scriptedModel) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+type upperTool struct {
+	calls int
+}
+
+func (t *upperTool) Name() string        { return "Upper Case" }
+func (t *upperTool) Description() string { return "upper cases the input" }
+func (t *upperTool) Call(_ context.Context, input string) (string, error) {
+	t.calls++
+	return strings.ToUpper(input), nil
+}
+
+func toolCallChoice(id, name, arguments string) *llms.ContentChoice {
+	return &llms.ContentChoice{ToolCalls: []llms.ToolCall{{
+		ID:           id,
+		Type:         "function",
+		FunctionCall: &llms.FunctionCall{Name: name, Arguments: arguments},
+	}}}
+}
+
+func TestLocalExecutor(t *testing.T) {
+	t.Parallel()
+
+	call := toolCallChoice("call_1", "Upper_Case", `{"__arg1":"gopher"}`)
+	call.Content = "Let me check."
+	model := &scriptedModel{choices: []*llms.ContentChoice{
+		call,
+		{Content: "It is GOPHER."},
+		{Content: "You asked about gopher."},
+	}}
+	tool := &upperTool{}
+	le := NewLocalExecutor(model, &assistant.Assistant{Instructions: "be helpful"},
+		WithTools([]tools.Tool{tool}), WithStepTrace())
+
+	session, err := le.NewSession()
+	require.NoError(t, err)
+
+	result, err := session.Send(context.Background(), "upper case gopher")
+	require.NoError(t, err)
+	assert.Equal(t, "It is GOPHER.", result.Output)
+	assert.Equal(t, 1, tool.calls)
+	require.Len(t, result.Steps, 2)
+	assert.Equal(t, "GOPHER", *result.Steps[0].StepDetails.ToolCalls[0].Function.Output)
+	assert.Equal(t, runner.RunStepTypeMessageCreation, result.Steps[1].Type)
+
+	// the second model call sees the tool call and its output after the instructions.
+	second := model.calls[1]
+	require.Len(t, second, 4)
+	assert.Equal(t, llms.ChatMessageTypeSystem, second[0].Role)
+	// the text the model wrote with its tool calls stays in the history.
+	require.Len(t, second[2].Parts, 2)
+	assert.Equal(t, llms.TextContent{Text: "Let me check."}, second[2].Parts[0])
+	assert.Equal(t, llms.ChatMessageTypeTool, second[3].Role)
+	assert.Equal(t, llms.ToolCallResponse{ToolCallID: "call_1", Name: "Upper_Case", Content: "GOPHER"}, second[3].Parts[0])
+	assert.Equal(t, []bool{true, true}, model.toolsSet)
+
+	// the session keeps the conversation for the next input.
+	result, err = le.ResumeSession(session.ThreadID).Send(context.Background(), "what did I ask?")
+	require.NoError(t, err)
+	assert.Equal(t, "You asked about gopher.", result.Output)
+	assert.Len(t, model.calls[2], 6)
+	assert.Len(t, le.Messages(session.ThreadID), 6)
+}
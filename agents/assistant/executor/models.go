@@ -1,6 +1,12 @@
 package executor
 
-import "github.com/tmc/langchaingo/tools"
+import (
+	"time"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/tools"
+)
 
 type ExecutorOption func(*AgentExecutor)
 
@@ -10,3 +16,114 @@ func WithTools(tools []tools.Tool) ExecutorOption {
 	}
 
 }
+
+// WithStepTrace makes Invoke return the steps taken by the assistant,
+// including tool calls, code interpreter outputs and file_search results.
+func WithStepTrace() ExecutorOption {
+	return func(a *AgentExecutor) {
+		a.stepTrace = true
+	}
+}
+
+// WithPollInterval sets how often the status of a run is checked. Defaults to one second.
+func WithPollInterval(interval time.Duration) ExecutorOption {
+	return func(a *AgentExecutor) {
+		a.PollInterval = interval
+	}
+}
+
+// WithThreadStore keeps the thread of each conversation in store, so SessionFor
+// resumes it. Threads idle for longer than ttl are deleted, a zero ttl keeps them forever.
+func WithThreadStore(store ThreadStore, ttl time.Duration) ExecutorOption {
+	return func(a *AgentExecutor) {
+		a.ThreadStore = store
+		a.ThreadTTL = ttl
+	}
+}
+
+// WithCallOptions sets the options of the calls to the model of a LocalExecutor.
+func WithCallOptions(opts ...llms.CallOption) ExecutorOption {
+	return func(a *AgentExecutor) {
+		a.callOptions = append(a.callOptions, opts...)
+	}
+}
+
+// WithToolApproval sets the approval policy of a tool, by name.
+func WithToolApproval(toolName string, policy ApprovalPolicy) ExecutorOption {
+	return func(a *AgentExecutor) {
+		if a.approvalPolicies == nil {
+			a.approvalPolicies = map[string]ApprovalPolicy{}
+		}
+		a.approvalPolicies[toolName] = policy
+	}
+}
+
+// WithDefaultApproval sets the approval policy of the tools without their own. Defaults to ApprovalAlways.
+func WithDefaultApproval(policy ApprovalPolicy) ExecutorOption {
+	return func(a *AgentExecutor) {
+		a.defaultApproval = policy
+	}
+}
+
+// WithApprover sets who decides on the calls of the tools with the ApprovalAsk policy.
+func WithApprover(approver Approver) ExecutorOption {
+	return func(a *AgentExecutor) {
+		a.approver = approver
+	}
+}
+
+// WithLimits sets the safeguards that stop a run, such as the maximum number
+// of tool calls or the detection of repeated calls.
+func WithLimits(limits Limits) ExecutorOption {
+	return func(a *AgentExecutor) {
+		a.limits = limits
+	}
+}
+
+// WithEvents sends the lifecycle events of the runs to ch: status changes,
+// tool calls, messages, usage and how the run ended. Sends block until ch is
+// read or the context of the run is done, so use a buffered channel or read it
+// in another goroutine. Runs are then streamed, so the answer is sent in
+// EventMessageDelta events as it is written.
+func WithEvents(ch chan<- Event) ExecutorOption {
+	return func(a *AgentExecutor) {
+		a.events = ch
+	}
+}
+
+// WithCallbacksHandler reports the runs to a langchaingo callbacks handler,
+// as chain, agent, tool and streaming callbacks. Runs are then streamed, as
+// with WithEvents.
+func WithCallbacksHandler(handler callbacks.Handler) ExecutorOption {
+	return func(a *AgentExecutor) {
+		a.callbacksHandler = handler
+	}
+}
+
+// WithToolTimeout sets how long a tool, by name, can take to answer.
+func WithToolTimeout(toolName string, timeout time.Duration) ExecutorOption {
+	return func(a *AgentExecutor) {
+		if a.toolTimeouts == nil {
+			a.toolTimeouts = map[string]time.Duration{}
+		}
+		a.toolTimeouts[toolName] = timeout
+	}
+}
+
+// WithDefaultToolTimeout sets how long the tools without their own timeout
+// can take to answer. Defaults to no timeout.
+func WithDefaultToolTimeout(timeout time.Duration) ExecutorOption {
+	return func(a *AgentExecutor) {
+		a.defaultToolTimeout = timeout
+	}
+}
+
+// WithMaxToolOutput sets the size, in bytes, of the largest tool output sent
+// to the model, and how longer outputs are shortened. A negative max sends the
+// outputs whole. Defaults to DefaultMaxToolOutput and TruncateOutput.
+func WithMaxToolOutput(max int, strategy OutputStrategy) ExecutorOption {
+	return func(a *AgentExecutor) {
+		a.maxToolOutput = max
+		a.outputStrategy = strategy
+	}
+}
@@ -0,0 +1,216 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/devalexandre/mylangchaingo/agents/assistant"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/tools"
+)
+
+// DefaultMaxToolOutput is the size, in bytes, of the largest tool output
+// submitted to a run, the limit of the Assistants API.
+const DefaultMaxToolOutput = 512 * 1024
+
+// ToolErrorType is the kind of a ToolError.
+type ToolErrorType string
+
+const (
+	ToolErrorFailed           ToolErrorType = "tool_error"
+	ToolErrorTimeout          ToolErrorType = "timeout"
+	ToolErrorPanic            ToolErrorType = "panic"
+	ToolErrorInvalidArguments ToolErrorType = "invalid_arguments"
+	ToolErrorNotFound         ToolErrorType = "tool_not_found"
+)
+
+// ToolError is a failed tool call. It is not returned by the executors: it is
+// sent to the model as the output of the call, so the model can recover, and
+// reported in the EventToolEnd event.
+type ToolError struct {
+	Tool string
+	Type ToolErrorType
+	Err  error
+}
+
+func (e *ToolError) Error() string {
+	return fmt.Sprintf("tool %s: %s: %v", e.Tool, e.Type, e.Err)
+}
+
+func (e *ToolError) Unwrap() error {
+	return e.Err
+}
+
+// Output is the JSON sent to the model in place of the output of the tool.
+func (e *ToolError) Output() string {
+	output, _ := json.Marshal(map[string]string{
+		"status": "error",
+		"type":   string(e.Type),
+		"tool":   e.Tool,
+		"error":  e.Err.Error(),
+	})
+	return string(output)
+}
+
+// OutputStrategy shortens a tool output longer than max bytes.
+type OutputStrategy func(ctx context.Context, call ToolInvocation, output string, max int) (string, error)
+
+// TruncateOutput keeps the beginning of the output.
+func TruncateOutput(_ context.Context, _ ToolInvocation, output string, max int) (string, error) {
+	if len(output) <= max {
+		return output, nil
+	}
+
+	const marker = "\n[truncated: showing %d of %d bytes]"
+	keep := cut(output, max-len(fmt.Sprintf(marker, len(output), len(output))))
+	return output[:keep] + fmt.Sprintf(marker, keep, len(output)), nil
+}
+
+// TruncateMiddle keeps the beginning and the end of the output, where logs
+// and listings usually have what matters.
+func TruncateMiddle(_ context.Context, _ ToolInvocation, output string, max int) (string, error) {
+	if len(output) <= max {
+		return output, nil
+	}
+
+	const marker = "\n[... %d bytes truncated ...]\n"
+	keep := max - len(fmt.Sprintf(marker, len(output)))
+	if keep < 0 {
+		keep = 0
+	}
+	head := cut(output, keep/2)
+	tail := len(output) - (keep - head)
+	for tail < len(output) && !utf8.RuneStart(output[tail]) {
+		tail++
+	}
+	return output[:head] + fmt.Sprintf(marker, tail-head) + output[tail:], nil
+}
+
+// SummarizeOutput asks model to summarize the output. The whole output is sent
+// to the model, so it must fit in its context window.
+func SummarizeOutput(model llms.Model) OutputStrategy {
+	return func(ctx context.Context, call ToolInvocation, output string, max int) (string, error) {
+		prompt := fmt.Sprintf("The tool %s was called with %s and returned the output below. "+
+			"Summarize it in less than %d characters, keeping the facts needed to answer with it.\n\n%s",
+			call.Name, call.Arguments, max, output)
+
+		return llms.GenerateFromSinglePrompt(ctx, model, prompt)
+	}
+}
+
+// cut returns the length of the longest prefix of s up to max bytes that does
+// not split a rune.
+func cut(s string, max int) int {
+	if max <= 0 {
+		return 0
+	}
+	if max >= len(s) {
+		return len(s)
+	}
+	for max > 0 && !utf8.RuneStart(s[max]) {
+		max--
+	}
+	return max
+}
+
+// executeTool calls the tool with the timeout of the executor, turning its
+// errors and panics into a ToolError.
+func (ae *AgentExecutor) executeTool(ctx context.Context, tool tools.Tool, call ToolInvocation) (string, error) {
+	// extract __arg1 received in arguments
+	arg1, err := assistant.ExtractArg1(call.Arguments)
+	if err != nil {
+		return "", &ToolError{Tool: call.Name, Type: ToolErrorInvalidArguments, Err: err}
+	}
+
+	toolCtx := ctx
+	timeout := ae.toolTimeout(call.Name)
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		toolCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	type outcome struct {
+		output string
+		err    error
+	}
+	// Tools that ignore their context keep running after the timeout, but the
+	// run does not wait for them.
+	done := make(chan outcome, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- outcome{err: &ToolError{Tool: call.Name, Type: ToolErrorPanic, Err: fmt.Errorf("%v", r)}}
+			}
+		}()
+
+		output, err := tool.Call(toolCtx, arg1)
+		done <- outcome{output: output, err: err}
+	}()
+
+	var result outcome
+	select {
+	case result = <-done:
+	case <-toolCtx.Done():
+		result = outcome{err: toolCtx.Err()}
+	}
+
+	if ctx.Err() != nil {
+		// the run itself was cancelled
+		return "", ctx.Err()
+	}
+	if result.err == nil {
+		return result.output, nil
+	}
+
+	var toolErr *ToolError
+	switch {
+	case errors.As(result.err, &toolErr):
+		return "", toolErr
+	case toolCtx.Err() != nil:
+		return "", &ToolError{Tool: call.Name, Type: ToolErrorTimeout, Err: fmt.Errorf("no output after %s", timeout)}
+	default:
+		return "", &ToolError{Tool: call.Name, Type: ToolErrorFailed, Err: result.err}
+	}
+}
+
+// limitOutput shortens the output with the strategy of the executor when it
+// is longer than the maximum. Strategies that fail or return a longer output
+// fall back to TruncateOutput.
+func (ae *AgentExecutor) limitOutput(ctx context.Context, call ToolInvocation, output string) string {
+	max := ae.maxToolOutput
+	if max == 0 {
+		max = DefaultMaxToolOutput
+	}
+	if max < 0 || len(output) <= max {
+		return output
+	}
+
+	strategy := ae.outputStrategy
+	if strategy == nil {
+		strategy = TruncateOutput
+	}
+	shortened, err := strategy(ctx, call, output, max)
+	if err != nil || len(shortened) > max {
+		shortened, _ = TruncateOutput(ctx, call, output, max)
+	}
+	return shortened
+}
+
+// toolTimeout prefers the timeout set for the exact name over one whose
+// formatted name matches.
+func (ae *AgentExecutor) toolTimeout(name string) time.Duration {
+	if timeout, ok := ae.toolTimeouts[name]; ok {
+		return timeout
+	}
+	for tool, timeout := range ae.toolTimeouts {
+		if assistant.FormatString(tool) == name {
+			return timeout
+		}
+	}
+	return ae.defaultToolTimeout
+}
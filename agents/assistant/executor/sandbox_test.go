@@ -0,0 +1,167 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devalexandre/mylangchaingo/agents/assistant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/tools"
+)
+
+// funcTool is a tool named Fn that runs call.
+type funcTool struct {
+	call func(ctx context.Context, input string) (string, error)
+}
+
+func (t funcTool) Name() string        { return "Fn" }
+func (t funcTool) Description() string { return "runs a function" }
+func (t funcTool) Call(ctx context.Context, input string) (string, error) {
+	return t.call(ctx, input)
+}
+
+func TestToolSandbox(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		call    func(ctx context.Context, input string) (string, error)
+		args    string
+		errType ToolErrorType
+	}{
+		{
+			name: "panic",
+			call: func(context.Context, string) (string, error) { panic("boom") },
+			args: `{"__arg1":"x"}`, errType: ToolErrorPanic,
+		},
+		{
+			name: "timeout",
+			call: func(ctx context.Context, _ string) (string, error) {
+				<-ctx.Done()
+				return "", ctx.Err()
+			},
+			args: `{"__arg1":"x"}`, errType: ToolErrorTimeout,
+		},
+		{
+			name: "error",
+			call: func(context.Context, string) (string, error) { return "", errors.New("no such page") },
+			args: `{"__arg1":"x"}`, errType: ToolErrorFailed,
+		},
+		{
+			name: "invalid arguments",
+			call: func(context.Context, string) (string, error) { return "ok", nil },
+			args: `{"query":"x"}`, errType: ToolErrorInvalidArguments,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			events := make(chan Event, 4)
+			ae := NewAgentExecutor(&assistant.Assistant{},
+				WithTools([]tools.Tool{funcTool{call: tt.call}}),
+				WithToolTimeout("Fn", 10*time.Millisecond),
+				WithEvents(events),
+			)
+
+			output, err := ae.callTool(context.Background(), ToolInvocation{Name: "Fn", Arguments: tt.args})
+			require.NoError(t, err)
+			assert.Contains(t, output, `"status":"error"`)
+			assert.Contains(t, output, `"type":"`+string(tt.errType)+`"`)
+
+			<-events // tool start
+			end := <-events
+			var toolErr *ToolError
+			require.ErrorAs(t, end.Err, &toolErr)
+			assert.Equal(t, tt.errType, toolErr.Type)
+		})
+	}
+}
+
+func TestToolTimeoutPrefersExactName(t *testing.T) {
+	t.Parallel()
+
+	ae := NewAgentExecutor(&assistant.Assistant{},
+		WithToolTimeout("Upper Case", time.Minute),
+		WithToolTimeout("Upper_Case", time.Second),
+	)
+	// map order is random, so a single lookup could pass by chance
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, time.Second, ae.toolTimeout("Upper_Case"))
+	}
+}
+
+func TestToolNotFound(t *testing.T) {
+	t.Parallel()
+
+	model := &scriptedModel{choices: []*llms.ContentChoice{
+		toolCallChoice("call_1", "Lower_Case", `{"__arg1":"X"}`),
+		{Content: "I can't lower case it."},
+	}}
+	le := NewLocalExecutor(model, &assistant.Assistant{}, WithTools([]tools.Tool{&upperTool{}}))
+
+	result, err := le.Invoke("go")
+	require.NoError(t, err)
+	assert.Equal(t, "I can't lower case it.", result.Output)
+
+	history := le.Messages(result.ThreadID)
+	assert.JSONEq(t, `{"status":"error","type":"tool_not_found","tool":"Lower_Case","error":"no tool with this name"}`,
+		history[2].Parts[0].(llms.ToolCallResponse).Content)
+}
+
+func TestToolOutputLimit(t *testing.T) {
+	t.Parallel()
+
+	long := funcTool{call: func(context.Context, string) (string, error) {
+		return strings.Repeat("a", 100) + strings.Repeat("é", 100) + strings.Repeat("z", 100), nil
+	}}
+	call := ToolInvocation{Name: "Fn", Arguments: `{"__arg1":"x"}`}
+
+	ae := NewAgentExecutor(&assistant.Assistant{},
+		WithTools([]tools.Tool{long}),
+		WithMaxToolOutput(80, nil),
+	)
+	output, err := ae.callTool(context.Background(), call)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(output), 80)
+	assert.True(t, strings.HasPrefix(output, "aaa"))
+	assert.True(t, strings.HasSuffix(output, "[truncated: showing 42 of 400 bytes]"))
+
+	ae = NewAgentExecutor(&assistant.Assistant{},
+		WithTools([]tools.Tool{long}),
+		WithMaxToolOutput(150, TruncateMiddle),
+	)
+	output, err = ae.callTool(context.Background(), call)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(output), 150)
+	assert.True(t, strings.HasPrefix(output, "aaa"))
+	assert.True(t, strings.HasSuffix(output, "zzz"))
+	assert.Contains(t, output, "bytes truncated")
+
+	// a strategy that fails falls back to truncation
+	model := &scriptedModel{}
+	ae = NewAgentExecutor(&assistant.Assistant{},
+		WithTools([]tools.Tool{long}),
+		WithMaxToolOutput(80, SummarizeOutput(model)),
+	)
+	output, err = ae.callTool(context.Background(), call)
+	require.NoError(t, err)
+	assert.Contains(t, output, "[truncated")
+	require.Len(t, model.calls, 1)
+
+	model = &scriptedModel{choices: []*llms.ContentChoice{{Content: "100 a, 100 é and 100 z"}}}
+	ae = NewAgentExecutor(&assistant.Assistant{},
+		WithTools([]tools.Tool{long}),
+		WithMaxToolOutput(80, SummarizeOutput(model)),
+	)
+	output, err = ae.callTool(context.Background(), call)
+	require.NoError(t, err)
+	assert.Equal(t, "100 a, 100 é and 100 z", output)
+}
@@ -0,0 +1,159 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/devalexandre/mylangchaingo/agents/assistant/message"
+	"github.com/devalexandre/mylangchaingo/agents/assistant/runner"
+	"github.com/devalexandre/mylangchaingo/agents/assistant/thread"
+)
+
+// Session is a conversation with the agent kept in a single thread, so each
+// input is answered with the context of the previous ones.
+type Session struct {
+	ThreadID string
+
+	executor *AgentExecutor
+	backend  runBackend
+	key      string
+	mu       sync.Mutex
+}
+
+// runBackend runs the agent on a thread, with the Assistants API or locally.
+// A run stopped by the limits returns its partial answer with a *LimitError;
+// a run that failed once created returns a result holding only its IDs.
+type runBackend interface {
+	send(ctx context.Context, threadID, input string) (*Result, error)
+}
+
+// NewSession starts a conversation in a new thread.
+func (ae *AgentExecutor) NewSession() (*Session, error) {
+	threads, err := thread.CreateThread()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create thread: %w", err)
+	}
+
+	return ae.ResumeSession(threads.ID), nil
+}
+
+// ResumeSession continues the conversation of an existing thread, such as
+// one stored from Session.ThreadID.
+func (ae *AgentExecutor) ResumeSession(threadID string) *Session {
+	return &Session{ThreadID: threadID, executor: ae, backend: ae}
+}
+
+// Send adds the input to the thread, runs the agent and returns the messages
+// it created in this run. A thread only runs once at a time, so concurrent
+// calls are sent one after the other. When the run is stopped by the Limits
+// of the executor, the partial answer is returned along with a *LimitError.
+func (s *Session) Send(ctx context.Context, input string) (*Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ae := s.executor
+	result, err := s.backend.send(ctx, s.ThreadID, input)
+	var limitErr *LimitError
+	if err != nil && (result == nil || !errors.As(err, &limitErr)) {
+		runID := ""
+		if result != nil {
+			runID = result.RunID
+		}
+		ae.emitEnd(ctx, nil, s.ThreadID, runID, err)
+		return nil, err
+	}
+
+	if result.Usage != nil {
+		ae.emit(ctx, Event{Type: EventUsage, ThreadID: s.ThreadID, RunID: result.RunID, Usage: result.Usage})
+	}
+	ae.emitEnd(ctx, result, s.ThreadID, result.RunID, err)
+
+	if err := s.touch(ctx); err != nil {
+		return nil, err
+	}
+	return result, err
+}
+
+func (ae *AgentExecutor) send(ctx context.Context, threadID, input string) (*Result, error) {
+	if _, err := message.CreateMessage(threadID, "user", input); err != nil {
+		return nil, fmt.Errorf("failed to add message: %w", err)
+	}
+
+	var runID string
+	var run *runner.Runner
+	var err error
+	if ae.streams() {
+		runID, run, err = ae.streamRun(ctx, threadID, input)
+	} else {
+		runID, run, err = ae.pollRun(ctx, threadID, input)
+	}
+	if runID == "" {
+		return nil, err
+	}
+	// the run is only identified when it failed
+	failed := &Result{ThreadID: threadID, RunID: runID}
+	if err != nil {
+		var limitErr *LimitError
+		if errors.As(err, &limitErr) {
+			// return the partial answer written before the run was stopped
+			partial, files, _ := ae.runOutput(ctx, threadID, runID)
+			return &Result{Output: partial, ThreadID: threadID, RunID: runID, Files: files}, err
+		}
+		return failed, fmt.Errorf("failed to retrieve thread messages: %w", err)
+	}
+	output, files, err := ae.runOutput(ctx, threadID, runID)
+	if err != nil {
+		return failed, fmt.Errorf("failed to retrieve thread messages: %w", err)
+	}
+
+	result := &Result{
+		Output:   output,
+		ThreadID: threadID,
+		RunID:    runID,
+		Usage:    run.Usage,
+		Files:    files,
+	}
+	// the steps have the images of the code interpreter missing from the messages
+	if ae.stepTrace || usesCodeInterpreter(ae.Agent) {
+		steps, err := runner.ListAllRunSteps(threadID, runID, runner.IncludeFileSearchContent)
+		if err != nil {
+			return failed, fmt.Errorf("failed to list run steps: %w", err)
+		}
+		result.Files = appendStepFiles(result.Files, steps)
+		if ae.stepTrace {
+			result.Steps = steps
+		}
+	}
+
+	return result, nil
+}
+
+// pollRun creates a run and polls it until it completes. It returns the ID
+// of the run, once created, and the completed run.
+func (ae *AgentExecutor) pollRun(ctx context.Context, threadID, input string) (string, *runner.Runner, error) {
+	run, err := runner.CreateRun(ae.Agent.ID, threadID)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create run: %w", err)
+	}
+	runID := *run.Id
+	ae.emit(ctx, Event{Type: EventRunCreated, ThreadID: threadID, RunID: runID, Input: input})
+
+	run, err = ae.waitRun(ctx, threadID, runID)
+	return runID, run, err
+}
+
+// touch records the thread of a session created with SessionFor as just used.
+func (s *Session) touch(ctx context.Context) error {
+	if s.key == "" || s.executor.ThreadStore == nil {
+		return nil
+	}
+
+	entry := ThreadEntry{Key: s.key, ThreadID: s.ThreadID, UpdatedAt: time.Now()}
+	if err := s.executor.ThreadStore.Put(ctx, entry); err != nil {
+		return fmt.Errorf("failed to store thread of %s: %w", s.key, err)
+	}
+	return nil
+}
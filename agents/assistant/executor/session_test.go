@@ -0,0 +1,76 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/devalexandre/mylangchaingo/agents/assistant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeThreadAPI answers each run with a single message echoing its number.
+type fakeThreadAPI struct {
+	t       *testing.T
+	mu      sync.Mutex
+	threads int
+	runs    int
+}
+
+func (f *fakeThreadAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/threads":
+		f.threads++
+		fmt.Fprintf(w, `{"id":"thread_%d"}`, f.threads)
+	case r.Method == http.MethodPost && r.URL.Path == "/threads/thread_1/messages":
+		_, _ = io.WriteString(w, `{"id":"msg_user","role":"user","content":[]}`)
+	case r.Method == http.MethodPost && r.URL.Path == "/threads/thread_1/runs":
+		f.runs++
+		fmt.Fprintf(w, `{"id":"run_%d","status":"queued"}`, f.runs)
+	case r.Method == http.MethodGet && r.URL.Path == fmt.Sprintf("/threads/thread_1/runs/run_%d", f.runs):
+		fmt.Fprintf(w, `{"id":"run_%d","status":"completed"}`, f.runs)
+	case r.Method == http.MethodGet && r.URL.Path == "/threads/thread_1/messages":
+		assert.Equal(f.t, fmt.Sprintf("run_%d", f.runs), r.URL.Query().Get("run_id"))
+		fmt.Fprintf(w, `{"data":[
+			{"id":"a","role":"assistant","content":[{"type":"text","text":{"value":"answer %d"}}]},
+			{"id":"b","role":"assistant","content":[{"type":"text","text":{"value":"part two"}}]}]}`, f.runs)
+	default:
+		f.t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestSession(t *testing.T) {
+	api := &fakeThreadAPI{t: t}
+	srv := httptest.NewServer(api)
+	defer srv.Close()
+
+	baseURL := assistant.BaseURL
+	assistant.BaseURL = srv.URL
+	defer func() { assistant.BaseURL = baseURL }()
+
+	ae := NewAgentExecutor(&assistant.Assistant{ID: "asst_1"}, WithPollInterval(time.Millisecond))
+	session, err := ae.NewSession()
+	require.NoError(t, err)
+	assert.Equal(t, "thread_1", session.ThreadID)
+
+	result, err := session.Send(context.Background(), "first")
+	require.NoError(t, err)
+	assert.Equal(t, "answer 1\npart two", result.Output)
+
+	resumed := ae.ResumeSession(session.ThreadID)
+	result, err = resumed.Send(context.Background(), "second")
+	require.NoError(t, err)
+	assert.Equal(t, "run_2", result.RunID)
+	assert.Equal(t, "answer 2\npart two", result.Output)
+	assert.Equal(t, 1, api.threads)
+}
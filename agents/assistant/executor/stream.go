@@ -0,0 +1,148 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/devalexandre/mylangchaingo/agents/assistant/runner"
+)
+
+// streams reports whether runs of the Assistants API are streamed rather than
+// polled. They are streamed when someone listens to the events, so the answer
+// is sent in EventMessageDelta as it is written.
+func (ae *AgentExecutor) streams() bool {
+	return ae.events != nil || ae.callbacksHandler != nil
+}
+
+// runStream follows a streamed run, emitting its events.
+type runStream struct {
+	ae       *AgentExecutor
+	threadID string
+	input    string
+	budget   *runBudget
+	runID    string
+	run      *runner.Runner
+	status   string
+	tokens   int
+}
+
+// streamRun creates a run and follows its events until it completes,
+// executing the tools it asks for. It returns the ID of the run, once created,
+// and the completed run. When a limit is exceeded the run is cancelled and a
+// *LimitError returned.
+func (ae *AgentExecutor) streamRun(ctx context.Context, threadID, input string) (string, *runner.Runner, error) {
+	stream, err := runner.CreateRunStream(ae.Agent.ID, threadID)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create run: %w", err)
+	}
+
+	rs := &runStream{ae: ae, threadID: threadID, input: input, budget: ae.newBudget()}
+	for {
+		err := rs.read(ctx, stream)
+		var limitErr *LimitError
+		if errors.As(err, &limitErr) {
+			return rs.runID, nil, ae.stopRun(threadID, rs.runID, err)
+		}
+		if err != nil {
+			return rs.runID, nil, err
+		}
+		if rs.run == nil {
+			return "", nil, errors.New("run stream ended before the run was created")
+		}
+
+		switch {
+		case rs.status == runner.StatusCompleted:
+			return rs.runID, rs.run, nil
+		case rs.status == runner.StatusRequiresAction && rs.run.RequiredAction != nil:
+			if err := rs.budget.round(); err != nil {
+				return rs.runID, nil, ae.stopRun(threadID, rs.runID, err)
+			}
+			toolCalls := rs.run.RequiredAction.SubmitToolOutputs.ToolCalls
+			outputs, err := ae.toolOutputs(ctx, threadID, rs.runID, toolCalls, rs.budget)
+			if errors.As(err, &limitErr) {
+				return rs.runID, nil, ae.stopRun(threadID, rs.runID, limitErr)
+			}
+			if err != nil {
+				return rs.runID, nil, fmt.Errorf("failed to handle tools execution: %w", err)
+			}
+
+			stream, err = runner.SubmitToolOutputsStream(threadID, rs.runID, outputs)
+			if err != nil {
+				return rs.runID, nil, fmt.Errorf("failed to submit tool outputs: %w", err)
+			}
+		default:
+			if err := runFailure(rs.run, rs.status); err != nil {
+				return rs.runID, nil, err
+			}
+			return rs.runID, nil, fmt.Errorf("run stream ended with the run %s", rs.status)
+		}
+	}
+}
+
+// read emits the events of the stream until it ends, which happens when the
+// run ends or asks for tool outputs.
+func (rs *runStream) read(ctx context.Context, stream *runner.Stream) error {
+	defer stream.Close()
+	// the request has no context, so closing the stream is what stops it
+	stop := context.AfterFunc(ctx, func() { stream.Close() })
+	defer stop()
+
+	ae := rs.ae
+	for stream.Next() {
+		event := stream.Event()
+		switch {
+		case event.Event == runner.EventMessageDelta:
+			if text := event.Text(); text != "" {
+				ae.emit(ctx, Event{Type: EventMessageDelta, ThreadID: rs.threadID, RunID: rs.runID, Text: text})
+			}
+		case event.Event == runner.EventRunStepCompleted:
+			// the run only reports its usage once it ends
+			var step runner.RunStep
+			if err := json.Unmarshal(event.Data, &step); err != nil {
+				return fmt.Errorf("failed to decode run step: %w", err)
+			}
+			if step.Usage != nil {
+				rs.tokens += step.Usage.TotalTokens
+				if err := rs.budget.usage(rs.tokens); err != nil {
+					return err
+				}
+			}
+		case strings.HasPrefix(event.Event, "thread.run.") && !strings.HasPrefix(event.Event, "thread.run.step."):
+			run, err := event.Runner()
+			if err != nil {
+				return fmt.Errorf("failed to decode run: %w", err)
+			}
+			rs.update(ctx, run)
+		}
+
+		if err := rs.budget.elapsed(); err != nil {
+			return err
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return stream.Err()
+}
+
+// update records the new state of the run.
+func (rs *runStream) update(ctx context.Context, run *runner.Runner) {
+	ae := rs.ae
+	if rs.runID == "" {
+		rs.runID = run.GetID()
+		ae.emit(ctx, Event{Type: EventRunCreated, ThreadID: rs.threadID, RunID: rs.runID, Input: rs.input})
+	}
+
+	var status string
+	if run.Status != nil {
+		status = *run.Status
+	}
+	if status != rs.status {
+		ae.emit(ctx, Event{Type: EventStatusChanged, ThreadID: rs.threadID, RunID: rs.runID, Status: status})
+		rs.status = status
+	}
+	rs.run = run
+}
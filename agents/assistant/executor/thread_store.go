@@ -0,0 +1,288 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/devalexandre/mylangchaingo/agents/assistant"
+	"github.com/devalexandre/mylangchaingo/agents/assistant/thread"
+	"github.com/devalexandre/mylangchaingo/internal/atomicfile"
+)
+
+// ThreadEntry maps a conversation key, such as the ID of a chat in your
+// application, to the thread that holds the conversation.
+type ThreadEntry struct {
+	Key       string    `json:"key"`
+	ThreadID  string    `json:"thread_id"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ThreadStore keeps the thread of each conversation so sessions can be resumed.
+type ThreadStore interface {
+	// Get returns the entry of the key and whether it was found.
+	Get(ctx context.Context, key string) (ThreadEntry, bool, error)
+	// Put creates or replaces the entry of entry.Key.
+	Put(ctx context.Context, entry ThreadEntry) error
+	// Delete removes the entry of the key. Missing keys are not an error.
+	Delete(ctx context.Context, key string) error
+	// Expired returns the entries last updated before the given time.
+	Expired(ctx context.Context, before time.Time) ([]ThreadEntry, error)
+}
+
+// SessionFor returns the session of a conversation, creating its thread the
+// first time the key is seen or when the previous one expired. Concurrent
+// calls for the same key share the thread; the store does not coordinate
+// executors of different processes.
+func (ae *AgentExecutor) SessionFor(ctx context.Context, key string) (*Session, error) {
+	if ae.ThreadStore == nil {
+		return nil, errors.New("executor has no thread store")
+	}
+
+	unlock := ae.sessionLocks.lock(key)
+	defer unlock()
+
+	entry, ok, err := ae.ThreadStore.Get(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get thread of %s: %w", key, err)
+	}
+	if ok && ae.ThreadTTL > 0 && time.Since(entry.UpdatedAt) > ae.ThreadTTL {
+		if err := ae.expireThread(ctx, entry); err != nil {
+			return nil, err
+		}
+		ok = false
+	}
+
+	var session *Session
+	if ok {
+		session = ae.ResumeSession(entry.ThreadID)
+	} else {
+		session, err = ae.NewSession()
+		if err != nil {
+			return nil, err
+		}
+	}
+	session.key = key
+
+	if err := session.touch(ctx); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// ExpireThreads deletes the threads of the conversations idle for longer than
+// the TTL of the executor, both remotely and from the store. It returns how
+// many threads were deleted.
+func (ae *AgentExecutor) ExpireThreads(ctx context.Context) (int, error) {
+	if ae.ThreadStore == nil || ae.ThreadTTL <= 0 {
+		return 0, nil
+	}
+
+	entries, err := ae.ThreadStore.Expired(ctx, time.Now().Add(-ae.ThreadTTL))
+	if err != nil {
+		return 0, fmt.Errorf("failed to list expired threads: %w", err)
+	}
+
+	for i, entry := range entries {
+		if err := ae.expireThread(ctx, entry); err != nil {
+			return i, err
+		}
+	}
+	return len(entries), nil
+}
+
+func (ae *AgentExecutor) expireThread(ctx context.Context, entry ThreadEntry) error {
+	// a thread already deleted remotely only needs to be removed from the store
+	var apiErr *assistant.APIError
+	if _, err := thread.DeleteThread(entry.ThreadID); err != nil && !(errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound) {
+		return fmt.Errorf("failed to delete thread %s: %w", entry.ThreadID, err)
+	}
+
+	if err := ae.ThreadStore.Delete(ctx, entry.Key); err != nil {
+		return fmt.Errorf("failed to delete thread of %s: %w", entry.Key, err)
+	}
+	return nil
+}
+
+// keyLocks serializes the work on each key.
+type keyLocks struct {
+	mu    sync.Mutex
+	locks map[string]*keyLock
+}
+
+type keyLock struct {
+	sync.Mutex
+	refs int
+}
+
+// lock locks the key and returns the function unlocking it. Locks are
+// forgotten once nobody holds or waits for them.
+func (l *keyLocks) lock(key string) func() {
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = map[string]*keyLock{}
+	}
+	kl, ok := l.locks[key]
+	if !ok {
+		kl = &keyLock{}
+		l.locks[key] = kl
+	}
+	kl.refs++
+	l.mu.Unlock()
+
+	kl.Lock()
+	return func() {
+		kl.Unlock()
+
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		kl.refs--
+		if kl.refs == 0 {
+			delete(l.locks, key)
+		}
+	}
+}
+
+// MemoryThreadStore keeps the threads in memory. It is lost when the process exits.
+type MemoryThreadStore struct {
+	mu      sync.RWMutex
+	entries map[string]ThreadEntry
+}
+
+// NewMemoryThreadStore returns an empty in-memory store.
+func NewMemoryThreadStore() *MemoryThreadStore {
+	return &MemoryThreadStore{entries: map[string]ThreadEntry{}}
+}
+
+func (s *MemoryThreadStore) Get(_ context.Context, key string) (ThreadEntry, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, ok := s.entries[key]
+	return entry, ok, nil
+}
+
+func (s *MemoryThreadStore) Put(_ context.Context, entry ThreadEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[entry.Key] = entry
+	return nil
+}
+
+func (s *MemoryThreadStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
+
+func (s *MemoryThreadStore) Expired(_ context.Context, before time.Time) ([]ThreadEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return expiredEntries(s.entries, before), nil
+}
+
+// FileThreadStore keeps the threads in a JSON file, rewritten on every change.
+type FileThreadStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileThreadStore returns a store backed by the JSON file at path, which is
+// created on the first change.
+func NewFileThreadStore(path string) *FileThreadStore {
+	return &FileThreadStore{path: path}
+}
+
+func (s *FileThreadStore) Get(_ context.Context, key string) (ThreadEntry, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.load()
+	if err != nil {
+		return ThreadEntry{}, false, err
+	}
+	entry, ok := entries[key]
+	return entry, ok, nil
+}
+
+func (s *FileThreadStore) Put(_ context.Context, entry ThreadEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.load()
+	if err != nil {
+		return err
+	}
+	entries[entry.Key] = entry
+	return s.save(entries)
+}
+
+func (s *FileThreadStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := entries[key]; !ok {
+		return nil
+	}
+	delete(entries, key)
+	return s.save(entries)
+}
+
+func (s *FileThreadStore) Expired(_ context.Context, before time.Time) ([]ThreadEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	return expiredEntries(entries, before), nil
+}
+
+func (s *FileThreadStore) load() (map[string]ThreadEntry, error) {
+	entries := map[string]ThreadEntry{}
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to decode thread store %s: %w", s.path, err)
+	}
+	return entries, nil
+}
+
+// save writes to a temporary file first so a crash never leaves a partial file.
+func (s *FileThreadStore) save(entries map[string]ThreadEntry) error {
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return atomicfile.Write(s.path, data)
+}
+
+func expiredEntries(entries map[string]ThreadEntry, before time.Time) []ThreadEntry {
+	var expired []ThreadEntry
+	for _, entry := range entries {
+		if entry.UpdatedAt.Before(before) {
+			expired = append(expired, entry)
+		}
+	}
+	return expired
+}
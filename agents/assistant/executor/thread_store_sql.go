@@ -0,0 +1,102 @@
+package executor
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/devalexandre/mylangchaingo/internal/sqltable"
+)
+
+const _defaultThreadTable = "assistant_threads"
+
+// SQLThreadStore keeps the threads in a database/sql table.
+type SQLThreadStore struct {
+	db    *sql.DB
+	table sqltable.Table
+}
+
+// SQLThreadStoreOption configures a SQLThreadStore.
+type SQLThreadStoreOption func(*SQLThreadStore)
+
+// WithThreadTable sets the table of the threads. Defaults to assistant_threads.
+func WithThreadTable(table string) SQLThreadStoreOption {
+	return func(s *SQLThreadStore) {
+		s.table.Name = table
+	}
+}
+
+// WithDollarPlaceholders is needed by PostgreSQL drivers, which expect $n
+// placeholders instead of ?. See internal/sqltable.
+func WithDollarPlaceholders() SQLThreadStoreOption {
+	return func(s *SQLThreadStore) {
+		s.table.Dollar = true
+	}
+}
+
+// NewSQLThreadStore returns a store backed by db, creating its table if needed.
+func NewSQLThreadStore(ctx context.Context, db *sql.DB, opts ...SQLThreadStoreOption) (*SQLThreadStore, error) {
+	s := &SQLThreadStore{db: db, table: sqltable.Table{Name: _defaultThreadTable}}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	err := s.table.Create(ctx, db,
+		"conversation_key VARCHAR(255) PRIMARY KEY",
+		"thread_id VARCHAR(255) NOT NULL",
+		"updated_at BIGINT NOT NULL",
+	)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *SQLThreadStore) Get(ctx context.Context, key string) (ThreadEntry, bool, error) {
+	entry := ThreadEntry{Key: key}
+	var updatedAt int64
+	err := s.db.QueryRowContext(ctx,
+		s.table.Query("SELECT thread_id, updated_at FROM %s WHERE conversation_key = ?"), key,
+	).Scan(&entry.ThreadID, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ThreadEntry{}, false, nil
+	}
+	if err != nil {
+		return ThreadEntry{}, false, err
+	}
+
+	entry.UpdatedAt = time.UnixMilli(updatedAt)
+	return entry, true, nil
+}
+
+func (s *SQLThreadStore) Put(ctx context.Context, entry ThreadEntry) error {
+	return s.table.Replace(ctx, s.db, []string{"conversation_key", "thread_id", "updated_at"},
+		entry.Key, entry.ThreadID, entry.UpdatedAt.UnixMilli())
+}
+
+func (s *SQLThreadStore) Delete(ctx context.Context, key string) error {
+	_, err := s.db.ExecContext(ctx, s.table.Query("DELETE FROM %s WHERE conversation_key = ?"), key)
+	return err
+}
+
+func (s *SQLThreadStore) Expired(ctx context.Context, before time.Time) ([]ThreadEntry, error) {
+	rows, err := s.db.QueryContext(ctx,
+		s.table.Query("SELECT conversation_key, thread_id, updated_at FROM %s WHERE updated_at < ?"), before.UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []ThreadEntry
+	for rows.Next() {
+		var entry ThreadEntry
+		var updatedAt int64
+		if err := rows.Scan(&entry.Key, &entry.ThreadID, &updatedAt); err != nil {
+			return nil, err
+		}
+		entry.UpdatedAt = time.UnixMilli(updatedAt)
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
@@ -0,0 +1,141 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/devalexandre/mylangchaingo/agents/assistant"
+	"github.com/devalexandre/mylangchaingo/internal/sqltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThreadStores(t *testing.T) {
+	t.Parallel()
+
+	db := sqltest.Open()
+	t.Cleanup(func() { db.Close() })
+	sqlStore, err := NewSQLThreadStore(context.Background(), db, WithThreadTable("threads"), WithDollarPlaceholders())
+	require.NoError(t, err)
+
+	stores := map[string]ThreadStore{
+		"memory": NewMemoryThreadStore(),
+		"file":   NewFileThreadStore(filepath.Join(t.TempDir(), "threads.json")),
+		"sql":    sqlStore,
+	}
+	for name, store := range stores {
+		store := store
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+
+			_, ok, err := store.Get(ctx, "chat-1")
+			require.NoError(t, err)
+			assert.False(t, ok)
+
+			old := time.Now().Add(-time.Hour).Truncate(time.Millisecond)
+			require.NoError(t, store.Put(ctx, ThreadEntry{Key: "chat-1", ThreadID: "thread_old", UpdatedAt: old}))
+			require.NoError(t, store.Put(ctx, ThreadEntry{Key: "chat-1", ThreadID: "thread_1", UpdatedAt: old}))
+			require.NoError(t, store.Put(ctx, ThreadEntry{Key: "chat-2", ThreadID: "thread_2", UpdatedAt: time.Now()}))
+
+			entry, ok, err := store.Get(ctx, "chat-1")
+			require.NoError(t, err)
+			require.True(t, ok)
+			assert.Equal(t, "thread_1", entry.ThreadID)
+			assert.True(t, old.Equal(entry.UpdatedAt))
+
+			expired, err := store.Expired(ctx, time.Now().Add(-time.Minute))
+			require.NoError(t, err)
+			require.Len(t, expired, 1)
+			assert.Equal(t, "chat-1", expired[0].Key)
+
+			require.NoError(t, store.Delete(ctx, "chat-1"))
+			require.NoError(t, store.Delete(ctx, "missing"))
+			_, ok, err = store.Get(ctx, "chat-1")
+			require.NoError(t, err)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestExpireThreads(t *testing.T) {
+	var deleted []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodDelete, r.Method)
+		deleted = append(deleted, r.URL.Path)
+		if r.URL.Path == "/threads/thread_gone" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"error":{"message":"No thread found"}}`)
+			return
+		}
+		_, _ = io.WriteString(w, `{"id":"thread_old","deleted":true}`)
+	}))
+	defer srv.Close()
+
+	baseURL := assistant.BaseURL
+	assistant.BaseURL = srv.URL
+	defer func() { assistant.BaseURL = baseURL }()
+
+	ctx := context.Background()
+	store := NewMemoryThreadStore()
+	old := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, store.Put(ctx, ThreadEntry{Key: "a", ThreadID: "thread_old", UpdatedAt: old}))
+	require.NoError(t, store.Put(ctx, ThreadEntry{Key: "b", ThreadID: "thread_gone", UpdatedAt: old}))
+	require.NoError(t, store.Put(ctx, ThreadEntry{Key: "c", ThreadID: "thread_new", UpdatedAt: time.Now()}))
+
+	ae := NewAgentExecutor(&assistant.Assistant{ID: "asst_1"}, WithThreadStore(store, time.Hour))
+	n, err := ae.ExpireThreads(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.ElementsMatch(t, []string{"/threads/thread_old", "/threads/thread_gone"}, deleted)
+
+	_, ok, err := store.Get(ctx, "c")
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestSessionForCreatesOneThreadPerKey(t *testing.T) {
+	var mu sync.Mutex
+	created := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/threads", r.URL.Path)
+		mu.Lock()
+		created++
+		id := created
+		mu.Unlock()
+		// give the other calls time to miss the store
+		time.Sleep(10 * time.Millisecond)
+		fmt.Fprintf(w, `{"id":"thread_%d"}`, id)
+	}))
+	defer srv.Close()
+
+	baseURL := assistant.BaseURL
+	assistant.BaseURL = srv.URL
+	defer func() { assistant.BaseURL = baseURL }()
+
+	ae := NewAgentExecutor(&assistant.Assistant{ID: "asst_1"}, WithThreadStore(NewMemoryThreadStore(), time.Hour))
+	threads := make([]string, 5)
+	var wg sync.WaitGroup
+	for i := range threads {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			session, err := ae.SessionFor(context.Background(), "chat-1")
+			assert.NoError(t, err)
+			threads[i] = session.ThreadID
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, created)
+	for _, threadID := range threads {
+		assert.Equal(t, "thread_1", threadID)
+	}
+}
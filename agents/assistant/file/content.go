@@ -0,0 +1,71 @@
+package file
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+
+	"github.com/devalexandre/mylangchaingo/agents/assistant"
+	"github.com/devalexandre/mylangchaingo/internal/atomicfile"
+)
+
+// RetrieveFileContent returns the content of a file, such as the charts and
+// CSVs generated by the code interpreter. The caller must close it.
+func RetrieveFileContent(fileID string) (io.ReadCloser, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/files/%s/content", assistant.BaseURL, fileID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := assistant.DoStream(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download file %s: %w", fileID, err)
+	}
+
+	return resp.Body, nil
+}
+
+// DownloadFile copies the content of a file to w.
+func DownloadFile(fileID string, w io.Writer) (int64, error) {
+	content, err := RetrieveFileContent(fileID)
+	if err != nil {
+		return 0, err
+	}
+	defer content.Close()
+
+	n, err := io.Copy(w, content)
+	if err != nil {
+		return n, fmt.Errorf("failed to download file %s: %w", fileID, err)
+	}
+	return n, nil
+}
+
+// DownloadFileToDir saves a file in dir, under its file name without the
+// directories of the sandbox, and returns its path. Files without a name are
+// saved under their ID. Existing files are never replaced: when the name is
+// taken a number is added to it, as in chart-1.png.
+func DownloadFileToDir(fileID, dir string) (string, error) {
+	info, err := RetrieveFile(fileID)
+	if err != nil {
+		return "", err
+	}
+
+	name := filepath.Base(filepath.FromSlash(info.Filename))
+	if name == "." || name == string(filepath.Separator) {
+		name = fileID
+	}
+
+	content, err := RetrieveFileContent(fileID)
+	if err != nil {
+		return "", err
+	}
+	defer content.Close()
+
+	// failed downloads leave no partial file behind
+	path, err := atomicfile.WriteNew(filepath.Join(dir, name), content)
+	if err != nil {
+		return "", fmt.Errorf("failed to download file %s: %w", fileID, err)
+	}
+	return path, nil
+}
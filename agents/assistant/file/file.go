@@ -0,0 +1,158 @@
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/devalexandre/mylangchaingo/agents/assistant"
+	"github.com/devalexandre/mylangchaingo/internal/formdata"
+)
+
+// Purpose is the intended use of an uploaded file.
+type Purpose string
+
+const (
+	PurposeAssistants Purpose = "assistants"
+	PurposeVision     Purpose = "vision"
+	PurposeBatch      Purpose = "batch"
+	PurposeFineTune   Purpose = "fine-tune"
+)
+
+type File struct {
+	ID            string  `json:"id"`
+	Object        string  `json:"object"`
+	Bytes         int64   `json:"bytes"`
+	CreatedAt     int     `json:"created_at"`
+	Filename      string  `json:"filename"`
+	Purpose       Purpose `json:"purpose"`
+	Status        string  `json:"status,omitempty"`
+	StatusDetails string  `json:"status_details,omitempty"`
+}
+
+// GetID returns the ID of the file.
+func (f File) GetID() string {
+	return f.ID
+}
+
+type Response struct {
+	Object  string `json:"object"`
+	Data    []File `json:"data"`
+	FirstId string `json:"first_id,omitempty"`
+	LastId  string `json:"last_id,omitempty"`
+	HasMore bool   `json:"has_more,omitempty"`
+}
+
+// UploadFile uploads a file, streaming its content from r.
+func UploadFile(filename string, r io.Reader, purpose Purpose) (*File, error) {
+	body, contentType := formdata.StreamFile(map[string]string{"purpose": string(purpose)}, filename, r)
+	defer body.Close()
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s/files", assistant.BaseURL), body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	respBody, err := assistant.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload file %s: %w", filename, err)
+	}
+
+	var file File
+	if err := json.Unmarshal(respBody, &file); err != nil {
+		return nil, err
+	}
+
+	return &file, nil
+}
+
+// ListFiles returns all the files, optionally only the ones with the given
+// purpose, reading every page.
+func ListFiles(purpose Purpose, opts ...assistant.ListOption) ([]File, error) {
+	return IterateFiles(purpose, opts...).Collect(context.Background())
+}
+
+// IterateFiles returns an iterator over the files, optionally only the ones
+// with the given purpose.
+func IterateFiles(purpose Purpose, opts ...assistant.ListOption) *assistant.Iterator[File] {
+	return assistant.NewIterator(func(opts ...assistant.ListOption) (*assistant.Page[File], error) {
+		response, err := listFiles(purpose, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return &assistant.Page[File]{Data: response.Data, HasMore: response.HasMore, LastID: response.LastId}, nil
+	}, opts...)
+}
+
+func listFiles(purpose Purpose, opts ...assistant.ListOption) (*Response, error) {
+	query := assistant.ListQuery(opts...)
+	if purpose != "" {
+		if query == "" {
+			query = "?"
+		} else {
+			query += "&"
+		}
+		query += "purpose=" + url.QueryEscape(string(purpose))
+	}
+
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/files%s", assistant.BaseURL, query), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	do, err := assistant.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var response Response
+	if err := json.Unmarshal(do, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// RetrieveFile returns the metadata of a file.
+func RetrieveFile(fileID string) (*File, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/files/%s", assistant.BaseURL, fileID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	do, err := assistant.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var file File
+	if err := json.Unmarshal(do, &file); err != nil {
+		return nil, err
+	}
+
+	return &file, nil
+}
+
+// Delete a file.
+func DeleteFile(fileID string) (*assistant.AssistantResponse, error) {
+	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/files/%s", assistant.BaseURL, fileID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	do, err := assistant.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var response assistant.AssistantResponse
+	if err := json.Unmarshal(do, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
@@ -0,0 +1,108 @@
+package file
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/devalexandre/mylangchaingo/agents/assistant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUploadFile(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/files", r.URL.Path)
+		assert.True(t, strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data"))
+
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		assert.Equal(t, "assistants", r.FormValue("purpose"))
+		part, header, err := r.FormFile("file")
+		require.NoError(t, err)
+		content, err := io.ReadAll(part)
+		require.NoError(t, err)
+		assert.Equal(t, "report.md", header.Filename)
+		assert.Equal(t, "# Report", string(content))
+
+		fmt.Fprintf(w, `{"id":"file_1","filename":%q,"bytes":%d,"purpose":"assistants"}`, header.Filename, len(content))
+	}))
+	defer srv.Close()
+
+	baseURL := assistant.BaseURL
+	assistant.BaseURL = srv.URL
+	defer func() { assistant.BaseURL = baseURL }()
+
+	uploaded, err := UploadFile("report.md", strings.NewReader("# Report"), PurposeAssistants)
+	require.NoError(t, err)
+	assert.Equal(t, &File{ID: "file_1", Filename: "report.md", Bytes: 8, Purpose: PurposeAssistants}, uploaded)
+}
+
+func TestListFilesReadsAllPages(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "vision", r.URL.Query().Get("purpose"))
+		if r.URL.Query().Get("after") == "" {
+			_, _ = io.WriteString(w, `{"data":[{"id":"file_1"}],"has_more":true,"last_id":"file_1"}`)
+			return
+		}
+		assert.Equal(t, "file_1", r.URL.Query().Get("after"))
+		_, _ = io.WriteString(w, `{"data":[{"id":"file_2"}]}`)
+	}))
+	defer srv.Close()
+
+	baseURL := assistant.BaseURL
+	assistant.BaseURL = srv.URL
+	defer func() { assistant.BaseURL = baseURL }()
+
+	files, err := ListFiles(PurposeVision, assistant.WithLimit(1))
+	require.NoError(t, err)
+	assert.Equal(t, []File{{ID: "file_1"}, {ID: "file_2"}}, files)
+}
+
+func TestDownloadFileToDirKeepsExistingFiles(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/files/file-1":
+			_, _ = io.WriteString(w, `{"id":"file-1","filename":"/mnt/data/chart.png"}`)
+		case "/files/file-2":
+			_, _ = io.WriteString(w, `{"id":"file-2","filename":"/mnt/data/chart.png"}`)
+		case "/files/file-1/content", "/files/file-2/content":
+			_, _ = io.WriteString(w, strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/files/"), "/content"))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	baseURL := assistant.BaseURL
+	assistant.BaseURL = srv.URL
+	defer func() { assistant.BaseURL = baseURL }()
+
+	dir := t.TempDir()
+	var paths []string
+	for _, fileID := range []string{"file-1", "file-2", "file-1"} {
+		path, err := DownloadFileToDir(fileID, dir)
+		require.NoError(t, err)
+		paths = append(paths, path)
+	}
+	assert.Equal(t, []string{
+		filepath.Join(dir, "chart.png"),
+		filepath.Join(dir, "chart-1.png"),
+		filepath.Join(dir, "chart-2.png"),
+	}, paths)
+
+	for i, fileID := range []string{"file-1", "file-2", "file-1"} {
+		content, err := os.ReadFile(paths[i])
+		require.NoError(t, err)
+		assert.Equal(t, fileID, string(content))
+	}
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 3)
+}
@@ -0,0 +1,107 @@
+package assistant
+
+import "context"
+
+// Page is a page returned by a list endpoint.
+type Page[T any] struct {
+	Data    []T
+	HasMore bool
+	LastID  string
+}
+
+// identified objects can be paged after when a page has no LastID.
+type identified interface {
+	GetID() string
+}
+
+// PageFunc fetches the page selected by the options.
+type PageFunc[T any] func(opts ...ListOption) (*Page[T], error)
+
+// Iterator walks all the pages of a list endpoint, fetching the next page
+// after the LastID of the previous one, or after the ID of its last object
+// when the page has no LastID and the objects have a GetID method.
+//
+//	it := message.IterateMessages(threadID)
+//	for it.Next(ctx) {
+//		msg := it.Current()
+//	}
+//	if err := it.Err(); err != nil { ... }
+type Iterator[T any] struct {
+	fetch   PageFunc[T]
+	opts    []ListOption
+	page    []T
+	index   int
+	after   string
+	hasMore bool
+	started bool
+	current T
+	err     error
+}
+
+// NewIterator returns an iterator that fetches pages with fetch. The options
+// are sent with every page request.
+func NewIterator[T any](fetch PageFunc[T], opts ...ListOption) *Iterator[T] {
+	return &Iterator[T]{fetch: fetch, opts: opts}
+}
+
+// Next advances to the next object, fetching a new page when needed. It
+// returns false when there are no more objects, on error or when ctx is done.
+func (it *Iterator[T]) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+
+	for it.index >= len(it.page) {
+		if it.started && (!it.hasMore || it.after == "") {
+			return false
+		}
+		if err := ctx.Err(); err != nil {
+			it.err = err
+			return false
+		}
+
+		opts := it.opts
+		if it.started {
+			opts = append(append([]ListOption{}, it.opts...), WithAfter(it.after))
+		}
+		page, err := it.fetch(opts...)
+		if err != nil {
+			it.err = err
+			return false
+		}
+
+		it.started = true
+		it.page = page.Data
+		it.index = 0
+		it.hasMore = page.HasMore
+		it.after = page.LastID
+		if it.after == "" && len(page.Data) > 0 {
+			if last, ok := any(page.Data[len(page.Data)-1]).(identified); ok {
+				it.after = last.GetID()
+			}
+		}
+	}
+
+	it.current = it.page[it.index]
+	it.index++
+	return true
+}
+
+// Current returns the object read by the last call to Next.
+func (it *Iterator[T]) Current() T {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// Collect reads all the remaining objects.
+func (it *Iterator[T]) Collect(ctx context.Context) ([]T, error) {
+	var all []T
+	for it.Next(ctx) {
+		all = append(all, it.Current())
+	}
+	return all, it.Err()
+}
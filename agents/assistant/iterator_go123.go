@@ -0,0 +1,28 @@
+//go:build go1.23
+
+package assistant
+
+import (
+	"context"
+	"iter"
+)
+
+// All returns the remaining objects as a sequence for range-over-func. The
+// iteration stops at the first error, which is yielded with a zero object.
+//
+//	for msg, err := range message.IterateMessages(threadID).All(ctx) {
+//		if err != nil { ... }
+//	}
+func (it *Iterator[T]) All(ctx context.Context) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for it.Next(ctx) {
+			if !yield(it.Current(), nil) {
+				return
+			}
+		}
+		if err := it.Err(); err != nil {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
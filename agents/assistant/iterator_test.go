@@ -0,0 +1,89 @@
+package assistant
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIterator(t *testing.T) {
+	t.Parallel()
+
+	pages := map[string]*Page[string]{
+		"":  {Data: []string{"a", "b"}, HasMore: true, LastID: "b"},
+		"b": {Data: []string{"c"}, HasMore: true, LastID: "c"},
+		"c": {Data: []string{"d"}, HasMore: false, LastID: "d"},
+	}
+	var queries []string
+	it := NewIterator(func(opts ...ListOption) (*Page[string], error) {
+		query := ListQuery(opts...)
+		queries = append(queries, query)
+		values, err := url.ParseQuery(query[1:])
+		require.NoError(t, err)
+		return pages[values.Get("after")], nil
+	}, WithLimit(2))
+
+	all, err := it.Collect(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c", "d"}, all)
+	assert.Equal(t, []string{"?limit=2", "?after=b&limit=2", "?after=c&limit=2"}, queries)
+	assert.False(t, it.Next(context.Background()))
+}
+
+type object struct{ id string }
+
+func (o object) GetID() string { return o.id }
+
+func TestIteratorFallsBackToLastObjectID(t *testing.T) {
+	t.Parallel()
+
+	var afters []string
+	it := NewIterator(func(opts ...ListOption) (*Page[object], error) {
+		params := &ListParams{}
+		for _, opt := range opts {
+			opt(params)
+		}
+		afters = append(afters, params.After)
+		if params.After == "" {
+			return &Page[object]{Data: []object{{"a"}, {"b"}}, HasMore: true}, nil
+		}
+		return &Page[object]{Data: []object{{"c"}}}, nil
+	})
+
+	all, err := it.Collect(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []object{{"a"}, {"b"}, {"c"}}, all)
+	assert.Equal(t, []string{"", "b"}, afters)
+}
+
+func TestIteratorError(t *testing.T) {
+	t.Parallel()
+
+	errPage := errors.New("page failed")
+	calls := 0
+	it := NewIterator(func(...ListOption) (*Page[int], error) {
+		calls++
+		if calls > 1 {
+			return nil, errPage
+		}
+		return &Page[int]{Data: []int{1}, HasMore: true, LastID: "1"}, nil
+	})
+
+	require.True(t, it.Next(context.Background()))
+	assert.Equal(t, 1, it.Current())
+	assert.False(t, it.Next(context.Background()))
+	require.ErrorIs(t, it.Err(), errPage)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	it = NewIterator(func(...ListOption) (*Page[int], error) {
+		t.Fatal("no page should be fetched with a cancelled context")
+		return nil, nil
+	})
+	assert.False(t, it.Next(ctx))
+	require.ErrorIs(t, it.Err(), context.Canceled)
+}
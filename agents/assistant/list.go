@@ -0,0 +1,95 @@
+package assistant
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// ListParams are the pagination parameters shared by the list endpoints.
+type ListParams struct {
+	Limit   int
+	Order   string // "asc" or "desc"
+	After   string
+	Before  string
+	RunID   string
+	Include []string
+}
+
+// ListOption configures a list request.
+type ListOption func(*ListParams)
+
+// WithLimit sets how many objects are returned per page, between 1 and 100.
+func WithLimit(limit int) ListOption {
+	return func(p *ListParams) {
+		p.Limit = limit
+	}
+}
+
+// WithOrder sorts the objects by their creation time, "asc" or "desc".
+func WithOrder(order string) ListOption {
+	return func(p *ListParams) {
+		p.Order = order
+	}
+}
+
+// WithAfter returns the objects after the one with the given ID.
+func WithAfter(id string) ListOption {
+	return func(p *ListParams) {
+		p.After = id
+	}
+}
+
+// WithBefore returns the objects before the one with the given ID.
+func WithBefore(id string) ListOption {
+	return func(p *ListParams) {
+		p.Before = id
+	}
+}
+
+// WithRunID only returns the messages created by the given run.
+func WithRunID(runID string) ListOption {
+	return func(p *ListParams) {
+		p.RunID = runID
+	}
+}
+
+// WithInclude asks for additional fields, such as the file_search result content of run steps.
+func WithInclude(fields ...string) ListOption {
+	return func(p *ListParams) {
+		p.Include = append(p.Include, fields...)
+	}
+}
+
+// ListQuery applies the options and returns them encoded as a query string,
+// including the leading "?", or an empty string when there is nothing to send.
+func ListQuery(opts ...ListOption) string {
+	params := &ListParams{}
+	for _, opt := range opts {
+		opt(params)
+	}
+
+	values := url.Values{}
+	if params.Limit > 0 {
+		values.Set("limit", strconv.Itoa(params.Limit))
+	}
+	if params.Order != "" {
+		values.Set("order", params.Order)
+	}
+	if params.After != "" {
+		values.Set("after", params.After)
+	}
+	if params.Before != "" {
+		values.Set("before", params.Before)
+	}
+	if params.RunID != "" {
+		values.Set("run_id", params.RunID)
+	}
+	for _, field := range params.Include {
+		values.Add("include[]", field)
+	}
+
+	if len(values) == 0 {
+		return ""
+	}
+	return "?" + values.Encode()
+}
@@ -0,0 +1,85 @@
+package message
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/devalexandre/mylangchaingo/agents/assistant/file"
+)
+
+// FileNameResolver returns the name of a file from its ID.
+type FileNameResolver func(fileID string) (string, error)
+
+// RetrieveFileName resolves file names with the Files API.
+func RetrieveFileName(fileID string) (string, error) {
+	f, err := file.RetrieveFile(fileID)
+	if err != nil {
+		return "", err
+	}
+	return f.Filename, nil
+}
+
+// RenderWithCitations returns the text of the content with the annotation
+// placeholders replaced by numbered references, such as "[1]", followed by a
+// footnote naming each file. A nil resolver uses RetrieveFileName.
+func RenderWithCitations(content Content, resolve FileNameResolver) (string, error) {
+	if resolve == nil {
+		resolve = RetrieveFileName
+	}
+
+	numbers := map[string]int{}
+	var footnotes []string
+	var texts []string
+	for _, part := range content {
+		if part.Type == ContentTypeRefusal {
+			texts = append(texts, part.Refusal)
+			continue
+		}
+		if part.Type != ContentTypeText || part.Text == nil {
+			continue
+		}
+
+		text := part.Text.Value
+		for _, annotation := range part.Text.Annotations {
+			fileID, quote := annotationFile(annotation)
+			if fileID == "" || annotation.Text == "" {
+				continue
+			}
+
+			number, ok := numbers[fileID]
+			if !ok {
+				name, err := resolve(fileID)
+				if err != nil {
+					return "", fmt.Errorf("failed to resolve file %s: %w", fileID, err)
+				}
+
+				number = len(numbers) + 1
+				numbers[fileID] = number
+				footnote := fmt.Sprintf("[%d] %s", number, name)
+				if quote != "" {
+					footnote += fmt.Sprintf(": %q", quote)
+				}
+				footnotes = append(footnotes, footnote)
+			}
+
+			text = strings.Replace(text, annotation.Text, fmt.Sprintf("[%d]", number), 1)
+		}
+		texts = append(texts, text)
+	}
+
+	rendered := strings.Join(texts, "\n")
+	if len(footnotes) > 0 {
+		rendered += "\n\n" + strings.Join(footnotes, "\n")
+	}
+	return rendered, nil
+}
+
+func annotationFile(annotation Annotation) (fileID, quote string) {
+	switch {
+	case annotation.FileCitation != nil:
+		return annotation.FileCitation.FileID, annotation.FileCitation.Quote
+	case annotation.FilePath != nil:
+		return annotation.FilePath.FileID, ""
+	}
+	return "", ""
+}
@@ -0,0 +1,159 @@
+package message
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/devalexandre/mylangchaingo/agents/assistant"
+)
+
+const (
+	ContentTypeText      = "text"
+	ContentTypeImageFile = "image_file"
+	ContentTypeImageURL  = "image_url"
+	ContentTypeRefusal   = "refusal"
+
+	AnnotationTypeFileCitation = "file_citation"
+	AnnotationTypeFilePath     = "file_path"
+)
+
+type FileCitation struct {
+	FileID string `json:"file_id"`
+	Quote  string `json:"quote,omitempty"`
+}
+
+type FilePath struct {
+	FileID string `json:"file_id"`
+}
+
+// Annotation points to a file cited by, or generated for, a text part.
+// Text is the placeholder in the value, e.g. "【4:0†source】".
+type Annotation struct {
+	Type         string        `json:"type"`
+	Text         string        `json:"text"`
+	StartIndex   int           `json:"start_index"`
+	EndIndex     int           `json:"end_index"`
+	FileCitation *FileCitation `json:"file_citation,omitempty"`
+	FilePath     *FilePath     `json:"file_path,omitempty"`
+}
+
+type Text struct {
+	Value       string       `json:"value"`
+	Annotations []Annotation `json:"annotations,omitempty"`
+}
+
+// ContentPart is one part of a message. Only the field matching Type is set.
+type ContentPart struct {
+	Type      string          `json:"type"`
+	Text      *Text           `json:"text,omitempty"`
+	ImageFile *assistant.File `json:"image_file,omitempty"`
+	ImageURL  *assistant.URL  `json:"image_url,omitempty"`
+	Refusal   string          `json:"refusal,omitempty"`
+}
+
+// TextPart returns a text content part.
+func TextPart(text string) ContentPart {
+	return ContentPart{Type: ContentTypeText, Text: &Text{Value: text}}
+}
+
+// ImageFilePart returns an image content part for an uploaded file with the vision purpose.
+// detail is "auto", "low" or "high", or empty for the default.
+func ImageFilePart(fileID, detail string) ContentPart {
+	return ContentPart{Type: ContentTypeImageFile, ImageFile: &assistant.File{FileID: fileID, Detail: detail}}
+}
+
+// ImageURLPart returns an image content part for an external image.
+// detail is "auto", "low" or "high", or empty for the default.
+func ImageURLPart(url, detail string) ContentPart {
+	return ContentPart{Type: ContentTypeImageURL, ImageURL: &assistant.URL{URL: url, Detail: detail}}
+}
+
+// Content is the content of a message. It is sent as a plain string when it
+// is a single text part, and can be decoded from either form.
+type Content []ContentPart
+
+// TextContent returns a content with a single text part.
+func TextContent(text string) Content {
+	return Content{TextPart(text)}
+}
+
+// Text returns the text parts joined by new lines. Refusals are included.
+func (c Content) Text() string {
+	var texts []string
+	for _, part := range c {
+		switch {
+		case part.Type == ContentTypeText && part.Text != nil:
+			texts = append(texts, part.Text.Value)
+		case part.Type == ContentTypeRefusal:
+			texts = append(texts, part.Refusal)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// Annotations returns the annotations of all the text parts.
+func (c Content) Annotations() []Annotation {
+	var annotations []Annotation
+	for _, part := range c {
+		if part.Text != nil {
+			annotations = append(annotations, part.Text.Annotations...)
+		}
+	}
+	return annotations
+}
+
+func (c Content) MarshalJSON() ([]byte, error) {
+	if len(c) == 1 && c[0].Type == ContentTypeText && c[0].Text != nil && len(c[0].Text.Annotations) == 0 {
+		return json.Marshal(c[0].Text.Value)
+	}
+	return json.Marshal([]ContentPart(c))
+}
+
+func (c *Content) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*c = nil
+		return nil
+	}
+
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*c = TextContent(text)
+		return nil
+	}
+
+	var parts []ContentPart
+	if err := json.Unmarshal(data, &parts); err != nil {
+		return err
+	}
+	*c = parts
+	return nil
+}
+
+// GeneratedFile is a file generated by the code interpreter that a message refers to.
+type GeneratedFile struct {
+	FileID string
+	// Path is the sandbox path the text links to, e.g. "sandbox:/mnt/data/sales.csv".
+	// It is empty for images.
+	Path  string
+	Image bool
+}
+
+// GeneratedFiles returns the images of the content and the files of its
+// file_path annotations.
+func (c Content) GeneratedFiles() []GeneratedFile {
+	var files []GeneratedFile
+	for _, part := range c {
+		if part.Type == ContentTypeImageFile && part.ImageFile != nil {
+			files = append(files, GeneratedFile{FileID: part.ImageFile.FileID, Image: true})
+		}
+		if part.Text == nil {
+			continue
+		}
+		for _, annotation := range part.Text.Annotations {
+			if annotation.Type == AnnotationTypeFilePath && annotation.FilePath != nil {
+				files = append(files, GeneratedFile{FileID: annotation.FilePath.FileID, Path: annotation.Text})
+			}
+		}
+	}
+	return files
+}
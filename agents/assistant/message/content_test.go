@@ -0,0 +1,65 @@
+package message
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const answerJSON = `{
+	"id":"msg_1","role":"assistant",
+	"content":[
+		{"type":"text","text":{"value":"Revenue grew 10%【4:0†source】 and costs fell【4:1†source】. See sandbox:/mnt/data/chart.csv",
+			"annotations":[
+				{"type":"file_citation","text":"【4:0†source】","start_index":17,"end_index":29,"file_citation":{"file_id":"file_report"}},
+				{"type":"file_citation","text":"【4:1†source】","start_index":45,"end_index":57,"file_citation":{"file_id":"file_report"}},
+				{"type":"file_path","text":"sandbox:/mnt/data/chart.csv","start_index":63,"end_index":90,"file_path":{"file_id":"file_chart"}}
+			]}},
+		{"type":"image_file","image_file":{"file_id":"file_img"}}
+	]
+}`
+
+func TestContentJSON(t *testing.T) {
+	t.Parallel()
+
+	var msg Message
+	require.NoError(t, json.Unmarshal([]byte(answerJSON), &msg))
+	require.Len(t, msg.Content, 2)
+	assert.Equal(t, "file_img", msg.Content[1].ImageFile.FileID)
+	assert.Len(t, msg.Content.Annotations(), 3)
+	assert.Equal(t, []GeneratedFile{
+		{FileID: "file_chart", Path: "sandbox:/mnt/data/chart.csv"},
+		{FileID: "file_img", Image: true},
+	}, msg.Content.GeneratedFiles())
+
+	var plain Message
+	require.NoError(t, json.Unmarshal([]byte(`{"role":"user","content":"hello"}`), &plain))
+	assert.Equal(t, "hello", plain.Content.Text())
+
+	body, err := json.Marshal(Message{Role: "user", Content: TextContent("hello")})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"role":"user","content":"hello"}`, string(body))
+
+	body, err = json.Marshal(Message{Role: "user", Content: Content{TextPart("what is this?"), ImageURLPart("https://example.com/a.png", "low")}})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"role":"user","content":[
+		{"type":"text","text":{"value":"what is this?"}},
+		{"type":"image_url","image_url":{"url":"https://example.com/a.png","detail":"low"}}
+	]}`, string(body))
+}
+
+func TestRenderWithCitations(t *testing.T) {
+	t.Parallel()
+
+	var msg Message
+	require.NoError(t, json.Unmarshal([]byte(answerJSON), &msg))
+
+	names := map[string]string{"file_report": "report.pdf", "file_chart": "chart.csv"}
+	rendered, err := RenderWithCitations(msg.Content, func(fileID string) (string, error) {
+		return names[fileID], nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Revenue grew 10%[1] and costs fell[1]. See [2]\n\n[1] report.pdf\n[2] chart.csv", rendered)
+}
@@ -14,13 +14,18 @@ type Message struct {
 	CreatedAt   int                     `json:"created_at,omitempty,omitempty"`
 	ThreadId    string                  `json:"thread_id,omitempty"`
 	Role        string                  `json:"role"`
-	Content     string                  `json:"content"`
+	Content     Content                 `json:"content"`
 	AssistantId string                  `json:"assistant_id,omitempty"`
 	RunId       string                  `json:"run_id,omitempty"`
 	Attachments []assistant.Attachments `json:"attachments,omitempty"`
 	Metadata    map[string]string       `json:"metadata,omitempty"`
 }
 
+// GetID returns the ID of the message.
+func (m Message) GetID() string {
+	return m.ID
+}
+
 type Response struct {
 	ID      string           `json:"ID"`
 	Object  string           `json:"object"`
@@ -31,19 +36,24 @@ type Response struct {
 	Deleted bool             `json:"deleted,omitempty"`
 }
 
-type MessageCreated struct {
-	Message
-	Content []struct {
-		Type string `json:"type"`
-		Text struct {
-			Value       string        `json:"value"`
-			Annotations []interface{} `json:"annotations"`
-		} `json:"text"`
-	} `json:"content"`
-}
+// MessageCreated is kept for compatibility, messages returned by the API are decoded into Message.
+type MessageCreated = Message
 
 // NewMessageinicializa um novo assistente, opcionalmente com um ID de assistente existente.
 func CreateMessage(threadID, role string, content string, opts ...MessageOption) (*Message, error) {
+	if len(content) == 0 {
+		message := &Message{Role: role}
+		for _, opt := range opts {
+			opt(message)
+		}
+		return message, nil
+	}
+
+	return CreateMessageWithContent(threadID, role, TextContent(content), opts...)
+}
+
+// CreateMessageWithContent creates a message made of several parts, such as text and images.
+func CreateMessageWithContent(threadID, role string, content Content, opts ...MessageOption) (*Message, error) {
 	message := &Message{
 		Role:    role,
 		Content: content,
@@ -53,10 +63,7 @@ func CreateMessage(threadID, role string, content string, opts ...MessageOption)
 	for _, opt := range opts {
 		opt(message)
 	}
-	if len(content) == 0 {
-		return message, nil
 
-	}
 	url := fmt.Sprintf("%s/threads/%s/messages", assistant.BaseURL, threadID)
 
 	bodyJSON, err := json.Marshal(message)
@@ -70,17 +77,22 @@ func CreateMessage(threadID, role string, content string, opts ...MessageOption)
 	}
 
 	respBody, err := assistant.Do(req)
-	var messageCreated MessageCreated
+	if err != nil {
+		return nil, err
+	}
+
+	var messageCreated Message
 	if err := json.Unmarshal(respBody, &messageCreated); err != nil {
 		return nil, err
 	}
 
-	return message, nil
+	return &messageCreated, nil
 }
 
-// Returns a list of messages for a given thread.
-func ListMessages(threadID string) (*Response, error) {
-	url := fmt.Sprintf("%s/threads/%s/messages", assistant.BaseURL, threadID)
+// Returns a page of messages for a given thread, newest first unless
+// assistant.WithOrder("asc") is given. Use IterateMessages to walk all the pages.
+func ListMessages(threadID string, opts ...assistant.ListOption) (*Response, error) {
+	url := fmt.Sprintf("%s/threads/%s/messages%s", assistant.BaseURL, threadID, assistant.ListQuery(opts...))
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
@@ -101,6 +113,17 @@ func ListMessages(threadID string) (*Response, error) {
 	return &response, nil
 }
 
+// IterateMessages returns an iterator over all the messages of a thread.
+func IterateMessages(threadID string, opts ...assistant.ListOption) *assistant.Iterator[Message] {
+	return assistant.NewIterator(func(opts ...assistant.ListOption) (*assistant.Page[Message], error) {
+		response, err := ListMessages(threadID, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return &assistant.Page[Message]{Data: response.Data, HasMore: response.HasMore, LastID: response.LastId}, nil
+	}, opts...)
+}
+
 func RetrieveMessage(threadID, messageId string) (*Message, error) {
 	url := fmt.Sprintf("%s/threads/%s/messages/%s", assistant.BaseURL, threadID, messageId)
 
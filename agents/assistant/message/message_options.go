@@ -42,7 +42,7 @@ func WithRole(role string) MessageOption {
 // WithContent configura o conteúdo da mensagem.
 func WithContent(content string) MessageOption {
 	return func(m *Message) {
-		m.Content = content
+		m.Content = TextContent(content)
 	}
 }
 
@@ -1,10 +1,14 @@
 package assistant
 
 import (
+	"encoding/json"
+
 	"github.com/tmc/langchaingo/llms"
 )
 
-const BaseURL = "https://api.openai.com/v1"
+// BaseURL is the base URL of the Assistants API. It can be changed to go
+// through a proxy or to point at a test server.
+var BaseURL = "https://api.openai.com/v1"
 
 type ToolType string
 
@@ -28,6 +32,11 @@ type FileSearch struct {
 	RankingOption RankingOption `json:"ranking_option"`
 }
 
+const (
+	ChunkingStrategyAuto   = "auto"
+	ChunkingStrategyStatic = "static"
+)
+
 type StaticChunkingStrategy struct {
 	MaxChunkSizeTokens int `json:"max_chunk_size_tokens"`
 	ChunkOverlapTokens int `json:"chunk_overlap_tokens"`
@@ -37,10 +46,53 @@ type ChunkingStrategy struct {
 	Static StaticChunkingStrategy `json:"static"`
 }
 
+// MarshalJSON only sends the static settings of static strategies, as the
+// API rejects them for the auto strategy.
+func (c ChunkingStrategy) MarshalJSON() ([]byte, error) {
+	type plain ChunkingStrategy
+	if c.Type == ChunkingStrategyStatic {
+		return json.Marshal(plain(c))
+	}
+	return json.Marshal(struct {
+		Type string `json:"type"`
+	}{c.Type})
+}
+
+// NewAutoChunkingStrategy lets OpenAI choose how files are chunked (800 tokens, 400 of overlap).
+func NewAutoChunkingStrategy() *ChunkingStrategy {
+	return &ChunkingStrategy{Type: ChunkingStrategyAuto}
+}
+
+// NewStaticChunkingStrategy chunks files in chunks of maxChunkSizeTokens
+// (100 to 4096) overlapping by chunkOverlapTokens (at most half the chunk size).
+func NewStaticChunkingStrategy(maxChunkSizeTokens, chunkOverlapTokens int) *ChunkingStrategy {
+	return &ChunkingStrategy{
+		Type: ChunkingStrategyStatic,
+		Static: StaticChunkingStrategy{
+			MaxChunkSizeTokens: maxChunkSizeTokens,
+			ChunkOverlapTokens: chunkOverlapTokens,
+		},
+	}
+}
+
 type VectorStore struct {
-	FileIDs          []string         `json:"file_ids,omitempty"`
-	ChunkingStrategy ChunkingStrategy `json:"chunking_strategy,omitempty"`
+	FileIDs          []string          `json:"file_ids,omitempty"`
+	ChunkingStrategy ChunkingStrategy  `json:"chunking_strategy,omitempty"`
+	Metadata         map[string]string `json:"metadata,omitempty"`
+}
+
+// MarshalJSON omits the chunking strategy when it is not set.
+func (v VectorStore) MarshalJSON() ([]byte, error) {
+	type plain VectorStore
+	if v.ChunkingStrategy.Type != "" {
+		return json.Marshal(plain(v))
+	}
+	return json.Marshal(struct {
+		plain
+		ChunkingStrategy *ChunkingStrategy `json:"chunking_strategy,omitempty"`
+	}{plain: plain(v)})
 }
+
 type CodeInterpreterToolResource struct {
 	FileIDs []string `json:"file_ids"`
 }
@@ -126,9 +178,17 @@ type Assistant struct {
 	Metadata     map[string]string `json:"metadata,omitempty"`
 }
 
+// GetID returns the ID of the assistant.
+func (a Assistant) GetID() string {
+	return a.ID
+}
+
 type AssistantResponse struct {
 	ID      string      `json:"id"`
 	Object  string      `json:"object"`
 	Data    []Assistant `json:"data,omitempty"`
+	FirstId string      `json:"first_id,omitempty"`
+	LastId  string      `json:"last_id,omitempty"`
+	HasMore bool        `json:"has_more,omitempty"`
 	Deleted bool        `json:"deleted,omitempty"`
 }
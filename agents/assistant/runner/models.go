@@ -6,6 +6,18 @@ import (
 	"github.com/devalexandre/mylangchaingo/agents/assistant/thread"
 )
 
+const (
+	StatusQueued         = "queued"
+	StatusInProgress     = "in_progress"
+	StatusRequiresAction = "requires_action"
+	StatusCancelling     = "cancelling"
+	StatusCancelled      = "cancelled"
+	StatusFailed         = "failed"
+	StatusCompleted      = "completed"
+	StatusIncomplete     = "incomplete"
+	StatusExpired        = "expired"
+)
+
 type TruncationStrategy struct {
 	Type         string      `json:"type,omitempty"`
 	LastMessages interface{} `json:"last_messages,omitempty"`
@@ -17,6 +29,31 @@ type Usage struct {
 	TotalTokens      int `json:"total_tokens,omitempty"`
 }
 
+type SubmitToolOutputsAction struct {
+	ToolCalls []assistant.ToolCall `json:"tool_calls"`
+}
+
+// RequiredAction is set when the run waits for the outputs of its tool calls.
+type RequiredAction struct {
+	Type              string                  `json:"type"`
+	SubmitToolOutputs SubmitToolOutputsAction `json:"submit_tool_outputs"`
+}
+
+type RunError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type IncompleteDetails struct {
+	Reason string `json:"reason"`
+}
+
+// ToolOutput is the output of a tool call, submitted with SubmitToolOutputs.
+type ToolOutput struct {
+	ToolCallID string `json:"tool_call_id"`
+	Output     string `json:"output"`
+}
+
 type Runner struct {
 	Id                     *string             `json:"id,omitempty"`
 	Object                 *string             `json:"object,omitempty"`
@@ -25,19 +62,19 @@ type Runner struct {
 	ThreadId               *string             `json:"thread_id,omitempty"`
 	Status                 *string             `json:"status,omitempty"`
 	StartedAt              *int                `json:"started_at,omitempty"`
-	ExpiresAt              *interface{}        `json:"expires_at,omitempty"`
-	CancelledAt            *interface{}        `json:"cancelled_at,omitempty"`
-	FailedAt               *interface{}        `json:"failed_at,omitempty"`
+	ExpiresAt              *int                `json:"expires_at,omitempty"`
+	CancelledAt            *int                `json:"cancelled_at,omitempty"`
+	FailedAt               *int                `json:"failed_at,omitempty"`
 	CompletedAt            *int                `json:"completed_at,omitempty"`
-	RequiredAction         interface{}         `json:"required_action,omitempty"`
-	LastError              *interface{}        `json:"last_error,omitempty"`
+	RequiredAction         *RequiredAction     `json:"required_action,omitempty"`
+	LastError              *RunError           `json:"last_error,omitempty"`
 	Model                  *string             `json:"model,omitempty"`
-	Instructions           *interface{}        `json:"instructions,omitempty"`
+	Instructions           *string             `json:"instructions,omitempty"`
 	AdditionalInstructions *string             `json:"additional_instructions,omitempty"`
 	AddicionalMessage      *[]message.Message  `json:"additional_messages,omitempty"`
 	Tools                  *[]assistant.Tool   `json:"tools,omitempty"`
 	Metadata               *map[string]string  `json:"metadata,omitempty"`
-	IncompleteDetails      *interface{}        `json:"incomplete_details,omitempty"`
+	IncompleteDetails      *IncompleteDetails  `json:"incomplete_details,omitempty"`
 	Usage                  *Usage              `json:"usage,omitempty"`
 	Temperature            *float64            `json:"temperature,omitempty"`
 	TopP                   *float64            `json:"top_p,omitempty"`
@@ -50,3 +87,121 @@ type Runner struct {
 	Stream                 *bool               `json:"stream,omitempty"`
 	Thread                 *thread.Thread      `json:"thread,omitempty"`
 }
+
+// GetID returns the ID of the run.
+func (r Runner) GetID() string {
+	if r.Id == nil {
+		return ""
+	}
+	return *r.Id
+}
+
+const (
+	RunStepTypeMessageCreation = "message_creation"
+	RunStepTypeToolCalls       = "tool_calls"
+
+	CodeInterpreterOutputLogs  = "logs"
+	CodeInterpreterOutputImage = "image"
+
+	// IncludeFileSearchContent asks for the content of the chunks found by file_search.
+	IncludeFileSearchContent = "step_details.tool_calls[*].file_search.results[*].content"
+)
+
+type MessageCreation struct {
+	MessageID string `json:"message_id"`
+}
+
+type CodeInterpreterImage struct {
+	FileID string `json:"file_id"`
+}
+
+// CodeInterpreterOutput is either the logs or an image produced by the code interpreter.
+type CodeInterpreterOutput struct {
+	Type  string                `json:"type"`
+	Logs  string                `json:"logs,omitempty"`
+	Image *CodeInterpreterImage `json:"image,omitempty"`
+}
+
+type CodeInterpreterCall struct {
+	Input   string                  `json:"input"`
+	Outputs []CodeInterpreterOutput `json:"outputs"`
+}
+
+type FileSearchResultContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type FileSearchResult struct {
+	FileID   string                    `json:"file_id"`
+	FileName string                    `json:"file_name"`
+	Score    float64                   `json:"score"`
+	Content  []FileSearchResultContent `json:"content,omitempty"`
+}
+
+type FileSearchCall struct {
+	RankingOptions *assistant.RankingOption `json:"ranking_options,omitempty"`
+	Results        []FileSearchResult       `json:"results,omitempty"`
+}
+
+type FunctionCall struct {
+	Name      string  `json:"name"`
+	Arguments string  `json:"arguments"`
+	Output    *string `json:"output"`
+}
+
+// StepToolCall is a tool call made during a run step. Only the field matching Type is set.
+type StepToolCall struct {
+	ID              string               `json:"id"`
+	Type            assistant.ToolType   `json:"type"`
+	CodeInterpreter *CodeInterpreterCall `json:"code_interpreter,omitempty"`
+	FileSearch      *FileSearchCall      `json:"file_search,omitempty"`
+	Function        *FunctionCall        `json:"function,omitempty"`
+}
+
+type StepDetails struct {
+	Type            string           `json:"type"`
+	MessageCreation *MessageCreation `json:"message_creation,omitempty"`
+	ToolCalls       []StepToolCall   `json:"tool_calls,omitempty"`
+}
+
+// RunStep is one of the steps taken by the assistant during a run.
+type RunStep struct {
+	ID          string            `json:"id"`
+	Object      string            `json:"object"`
+	CreatedAt   int               `json:"created_at"`
+	AssistantID string            `json:"assistant_id"`
+	ThreadID    string            `json:"thread_id"`
+	RunID       string            `json:"run_id"`
+	Type        string            `json:"type"`
+	Status      string            `json:"status"`
+	StepDetails StepDetails       `json:"step_details"`
+	LastError   *RunError         `json:"last_error,omitempty"`
+	ExpiredAt   *int              `json:"expired_at,omitempty"`
+	CancelledAt *int              `json:"cancelled_at,omitempty"`
+	FailedAt    *int              `json:"failed_at,omitempty"`
+	CompletedAt *int              `json:"completed_at,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	Usage       *Usage            `json:"usage,omitempty"`
+}
+
+// GetID returns the ID of the step.
+func (s RunStep) GetID() string {
+	return s.ID
+}
+
+type RunnerResponse struct {
+	Object  string   `json:"object"`
+	Data    []Runner `json:"data"`
+	FirstId string   `json:"first_id,omitempty"`
+	LastId  string   `json:"last_id,omitempty"`
+	HasMore bool     `json:"has_more,omitempty"`
+}
+
+type RunStepResponse struct {
+	Object  string    `json:"object"`
+	Data    []RunStep `json:"data"`
+	FirstId string    `json:"first_id,omitempty"`
+	LastId  string    `json:"last_id,omitempty"`
+	HasMore bool      `json:"has_more,omitempty"`
+}
@@ -33,7 +33,7 @@ func WithModel(model string) Option {
 	}
 }
 
-func WithInstructions(instructions interface{}) Option {
+func WithInstructions(instructions string) Option {
 	return func(r *Runner) {
 		r.Instructions = &instructions
 	}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github.com/devalexandre/mylangchaingo/agents/assistant"
 	"github.com/devalexandre/mylangchaingo/agents/assistant/thread"
+	"io"
 	"net/http"
 )
 
@@ -19,20 +20,40 @@ func CreateRun(assistantID, threadId string, opts ...Option) (*Runner, error) {
 		opt(runner)
 	}
 
-	runnerBody, err := json.Marshal(runner)
-	if err != nil {
-		return nil, err
+	url := fmt.Sprintf("%s/threads/%s/runs", assistant.BaseURL, threadId)
+
+	return sendRun("POST", url, runner)
+}
+
+// CreateThreadAndRun creates the thread and runs it in a single request.
+func CreateThreadAndRun(assistantID string, threads thread.Thread, opts ...Option) (*Runner, error) {
+
+	runner := &Runner{
+		AssistantId: assistantID,
+		Thread:      &threads,
 	}
 
-	//verificar se o threadId é nulo
-	thverifica, err := thread.RetrieveThread(threadId)
-	if err != nil {
-		return nil, err
+	for _, opt := range opts {
+		opt(runner)
 	}
 
-	url := fmt.Sprintf("%s/threads/%s/runs", assistant.BaseURL, thverifica.ID)
+	url := fmt.Sprintf("%s/threads/runs", assistant.BaseURL)
+
+	return sendRun("POST", url, runner)
+}
+
+// RetrieveRun returns the current state of a run.
+func RetrieveRun(threadID, runID string) (*Runner, error) {
+	url := fmt.Sprintf("%s/threads/%s/runs/%s", assistant.BaseURL, threadID, runID)
+
+	return sendRun("GET", url, nil)
+}
+
+// Returns a list of runs for a given thread.
+func ListRuns(threadID string, opts ...assistant.ListOption) (*RunnerResponse, error) {
+	url := fmt.Sprintf("%s/threads/%s/runs%s", assistant.BaseURL, threadID, assistant.ListQuery(opts...))
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(runnerBody))
+	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -42,28 +63,60 @@ func CreateRun(assistantID, threadId string, opts ...Option) (*Runner, error) {
 		return nil, err
 	}
 
-	var RunnerResponse Runner
-	if err := json.Unmarshal(respBody, &RunnerResponse); err != nil {
+	var response RunnerResponse
+	if err := json.Unmarshal(respBody, &response); err != nil {
 		return nil, err
 	}
 
-	return &RunnerResponse, nil
+	return &response, nil
 }
 
-func CreateThreadAndRun(assistantID string, threads thread.Thread, opts ...Option) (*Runner, error) {
+// IterateRuns returns an iterator over all the runs of a thread.
+func IterateRuns(threadID string, opts ...assistant.ListOption) *assistant.Iterator[Runner] {
+	return assistant.NewIterator(func(opts ...assistant.ListOption) (*assistant.Page[Runner], error) {
+		response, err := ListRuns(threadID, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return &assistant.Page[Runner]{Data: response.Data, HasMore: response.HasMore, LastID: response.LastId}, nil
+	}, opts...)
+}
 
-	runner := &Runner{
-		AssistantId: assistantID,
-		Thread:      &threads,
-	}
+// ModifyRun replaces the metadata of a run.
+func ModifyRun(threadID, runID string, metadata map[string]string) (*Runner, error) {
+	url := fmt.Sprintf("%s/threads/%s/runs/%s", assistant.BaseURL, threadID, runID)
 
-	for _, opt := range opts {
-		opt(runner)
-	}
+	return sendRun("POST", url, map[string]interface{}{"metadata": metadata})
+}
 
-	url := fmt.Sprintf("%s/threads/runs", assistant.BaseURL)
+// CancelRun cancels a run that is in progress. The run goes through the
+// cancelling status before it is cancelled.
+func CancelRun(threadID, runID string) (*Runner, error) {
+	url := fmt.Sprintf("%s/threads/%s/runs/%s/cancel", assistant.BaseURL, threadID, runID)
+
+	return sendRun("POST", url, nil)
+}
+
+// SubmitToolOutputs submits the outputs of all the tool calls of a run that
+// requires action. The outputs must be submitted in a single request.
+func SubmitToolOutputs(threadID, runID string, outputs []ToolOutput) (*Runner, error) {
+	url := fmt.Sprintf("%s/threads/%s/runs/%s/submit_tool_outputs", assistant.BaseURL, threadID, runID)
+
+	return sendRun("POST", url, map[string]interface{}{"tool_outputs": outputs})
+}
+
+// sendRun sends body as JSON, when not nil, and decodes the run in the response.
+func sendRun(method, url string, body interface{}) (*Runner, error) {
+	var reader io.Reader
+	if body != nil {
+		bodyJSON, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewBuffer(bodyJSON)
+	}
 
-	req, err := http.NewRequest("POST", url, nil)
+	req, err := http.NewRequest(method, url, reader)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,98 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/devalexandre/mylangchaingo/agents/assistant"
+	"github.com/devalexandre/mylangchaingo/agents/assistant/message"
+	"github.com/devalexandre/mylangchaingo/agents/assistant/thread"
+	"github.com/devalexandre/mylangchaingo/internal/assistanttest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateThreadAndRun(t *testing.T) {
+	assistanttest.UseServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/threads/runs", r.URL.Path)
+
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "asst_1", body["assistant_id"])
+		assert.Equal(t, "be brief", body["additional_instructions"])
+		assert.NotNil(t, body["thread"])
+
+		_, _ = io.WriteString(w, `{"id":"run_1","thread_id":"thread_1","status":"requires_action",
+			"required_action":{"type":"submit_tool_outputs","submit_tool_outputs":{"tool_calls":[
+				{"id":"call_1","type":"function","function":{"name":"search","arguments":"{}"}}]}},
+			"last_error":null,"incomplete_details":null}`)
+	})
+
+	run, err := CreateThreadAndRun("asst_1", thread.Thread{
+		Messages: []message.Message{{Role: "user", Content: message.TextContent("hi")}},
+	}, WithAdditionalInstructions("be brief"))
+	require.NoError(t, err)
+	assert.Equal(t, StatusRequiresAction, *run.Status)
+	require.NotNil(t, run.RequiredAction)
+	assert.Equal(t, "call_1", run.RequiredAction.SubmitToolOutputs.ToolCalls[0].ID)
+}
+
+func TestSubmitToolOutputsStream(t *testing.T) {
+	assistanttest.UseServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/threads/thread_1/runs/run_1/submit_tool_outputs", r.URL.Path)
+
+		var body struct {
+			ToolOutputs []ToolOutput `json:"tool_outputs"`
+			Stream      bool         `json:"stream"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.True(t, body.Stream)
+		assert.Equal(t, []ToolOutput{{ToolCallID: "call_1", Output: "42"}, {ToolCallID: "call_2", Output: "43"}}, body.ToolOutputs)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "event: thread.run.created\ndata: {\"id\":\"run_1\",\"status\":\"queued\"}\n\n")
+		fmt.Fprint(w, "event: thread.message.delta\ndata: {\"delta\":{\"content\":[{\"type\":\"text\",\"text\":{\"value\":\"The answer\"}}]}}\n\n")
+		fmt.Fprint(w, "event: thread.message.delta\ndata: {\"delta\":{\"content\":[{\"type\":\"text\",\"text\":{\"value\":\" is 42\"}}]}}\n\n")
+		fmt.Fprint(w, "event: thread.run.completed\ndata: {\"id\":\"run_1\",\"status\":\"completed\"}\n\n")
+		fmt.Fprint(w, "event: done\ndata: [DONE]\n\n")
+	})
+
+	stream, err := SubmitToolOutputsStream("thread_1", "run_1", []ToolOutput{
+		{ToolCallID: "call_1", Output: "42"},
+		{ToolCallID: "call_2", Output: "43"},
+	})
+	require.NoError(t, err)
+	defer stream.Close()
+
+	var text string
+	var last *Runner
+	for stream.Next() {
+		event := stream.Event()
+		text += event.Text()
+		if run, err := event.Runner(); err == nil {
+			last = run
+		}
+	}
+	require.NoError(t, stream.Err())
+	assert.Equal(t, "The answer is 42", text)
+	require.NotNil(t, last)
+	assert.Equal(t, StatusCompleted, *last.Status)
+}
+
+func TestStreamError(t *testing.T) {
+	assistanttest.UseServer(t, func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, "event: error\ndata: {\"message\":\"server overloaded\"}\n\n")
+	})
+
+	stream, err := CreateRunStream("asst_1", "thread_1")
+	require.NoError(t, err)
+	defer stream.Close()
+
+	assert.False(t, stream.Next())
+	var apiErr *assistant.APIError
+	require.ErrorAs(t, stream.Err(), &apiErr)
+	assert.Equal(t, "server overloaded", apiErr.Message)
+}
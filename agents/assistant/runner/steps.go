@@ -0,0 +1,76 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/devalexandre/mylangchaingo/agents/assistant"
+)
+
+// Returns the steps of a run. Use assistant.WithInclude(IncludeFileSearchContent)
+// to get the content of the file_search results.
+func ListRunSteps(threadID, runID string, opts ...assistant.ListOption) (*RunStepResponse, error) {
+	url := fmt.Sprintf("%s/threads/%s/runs/%s/steps%s", assistant.BaseURL, threadID, runID, assistant.ListQuery(opts...))
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := assistant.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var response RunStepResponse
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// RetrieveRunStep returns a single step of a run.
+func RetrieveRunStep(threadID, runID, stepID string, include ...string) (*RunStep, error) {
+	url := fmt.Sprintf("%s/threads/%s/runs/%s/steps/%s%s", assistant.BaseURL, threadID, runID, stepID,
+		assistant.ListQuery(assistant.WithInclude(include...)))
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := assistant.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var step RunStep
+	if err := json.Unmarshal(respBody, &step); err != nil {
+		return nil, err
+	}
+
+	return &step, nil
+}
+
+// IterateRunSteps returns an iterator over all the steps of a run.
+func IterateRunSteps(threadID, runID string, opts ...assistant.ListOption) *assistant.Iterator[RunStep] {
+	return assistant.NewIterator(func(opts ...assistant.ListOption) (*assistant.Page[RunStep], error) {
+		response, err := ListRunSteps(threadID, runID, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return &assistant.Page[RunStep]{Data: response.Data, HasMore: response.HasMore, LastID: response.LastId}, nil
+	}, opts...)
+}
+
+// ListAllRunSteps returns every step of a run, oldest first, following the pagination.
+func ListAllRunSteps(threadID, runID string, include ...string) ([]RunStep, error) {
+	return IterateRunSteps(threadID, runID,
+		assistant.WithLimit(100),
+		assistant.WithOrder("asc"),
+		assistant.WithInclude(include...),
+	).Collect(context.Background())
+}
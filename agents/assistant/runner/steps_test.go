@@ -0,0 +1,50 @@
+package runner
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/devalexandre/mylangchaingo/agents/assistant"
+	"github.com/devalexandre/mylangchaingo/internal/assistanttest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListAllRunSteps(t *testing.T) {
+	assistanttest.UseServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/threads/thread_1/runs/run_1/steps", r.URL.Path)
+		assert.Equal(t, "asc", r.URL.Query().Get("order"))
+		assert.Equal(t, []string{IncludeFileSearchContent}, r.URL.Query()["include[]"])
+
+		if r.URL.Query().Get("after") == "" {
+			_, _ = io.WriteString(w, `{"object":"list","has_more":true,"data":[{
+				"id":"step_1","type":"tool_calls","status":"completed",
+				"step_details":{"type":"tool_calls","tool_calls":[
+					{"id":"call_1","type":"code_interpreter","code_interpreter":{"input":"print(1)","outputs":[
+						{"type":"logs","logs":"1"},{"type":"image","image":{"file_id":"file_img"}}]}},
+					{"id":"call_2","type":"file_search","file_search":{"results":[
+						{"file_id":"file_doc","file_name":"doc.pdf","score":0.9,"content":[{"type":"text","text":"chunk"}]}]}}
+				]}}]}`)
+			return
+		}
+		assert.Equal(t, "step_1", r.URL.Query().Get("after"))
+		_, _ = io.WriteString(w, `{"object":"list","has_more":false,"data":[{
+			"id":"step_2","type":"message_creation","status":"completed",
+			"step_details":{"type":"message_creation","message_creation":{"message_id":"msg_1"}}}]}`)
+	})
+
+	steps, err := ListAllRunSteps("thread_1", "run_1", IncludeFileSearchContent)
+	require.NoError(t, err)
+	require.Len(t, steps, 2)
+
+	calls := steps[0].StepDetails.ToolCalls
+	require.Len(t, calls, 2)
+	assert.Equal(t, assistant.CodeInterpreterType, calls[0].Type)
+	assert.Equal(t, "1", calls[0].CodeInterpreter.Outputs[0].Logs)
+	assert.Equal(t, "file_img", calls[0].CodeInterpreter.Outputs[1].Image.FileID)
+	assert.Equal(t, "chunk", calls[1].FileSearch.Results[0].Content[0].Text)
+
+	assert.Equal(t, RunStepTypeMessageCreation, steps[1].Type)
+	assert.Equal(t, "msg_1", steps[1].StepDetails.MessageCreation.MessageID)
+}
@@ -0,0 +1,218 @@
+package runner
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/devalexandre/mylangchaingo/agents/assistant"
+)
+
+const (
+	EventRunCreated        = "thread.run.created"
+	EventRunRequiresAction = "thread.run.requires_action"
+	EventRunCompleted      = "thread.run.completed"
+	EventRunFailed         = "thread.run.failed"
+	EventRunStepCompleted  = "thread.run.step.completed"
+	EventMessageDelta      = "thread.message.delta"
+	EventMessageCompleted  = "thread.message.completed"
+	EventError             = "error"
+	EventDone              = "done"
+)
+
+// Event is a server-sent event of a streamed run.
+type Event struct {
+	Event string
+	Data  json.RawMessage
+}
+
+// Runner decodes the run carried by the thread.run.* events.
+func (e Event) Runner() (*Runner, error) {
+	if !strings.HasPrefix(e.Event, "thread.run.") || strings.HasPrefix(e.Event, "thread.run.step.") {
+		return nil, fmt.Errorf("event %s does not carry a run", e.Event)
+	}
+
+	var run Runner
+	if err := json.Unmarshal(e.Data, &run); err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// Text returns the text added by a thread.message.delta event.
+func (e Event) Text() string {
+	if e.Event != EventMessageDelta {
+		return ""
+	}
+
+	var delta struct {
+		Delta struct {
+			Content []struct {
+				Type string `json:"type"`
+				Text struct {
+					Value string `json:"value"`
+				} `json:"text"`
+			} `json:"content"`
+		} `json:"delta"`
+	}
+	if err := json.Unmarshal(e.Data, &delta); err != nil {
+		return ""
+	}
+
+	var text strings.Builder
+	for _, content := range delta.Delta.Content {
+		if content.Type == "text" {
+			text.WriteString(content.Text.Value)
+		}
+	}
+	return text.String()
+}
+
+// Stream reads the events of a streamed run.
+//
+//	for stream.Next() {
+//		fmt.Print(stream.Event().Text())
+//	}
+//	if err := stream.Err(); err != nil { ... }
+type Stream struct {
+	body    io.ReadCloser
+	scanner *bufio.Scanner
+	event   Event
+	err     error
+}
+
+// CreateRunStream creates a run and streams its events.
+func CreateRunStream(assistantID, threadId string, opts ...Option) (*Stream, error) {
+	runner := &Runner{
+		AssistantId: assistantID,
+	}
+
+	for _, opt := range opts {
+		opt(runner)
+	}
+	stream := true
+	runner.Stream = &stream
+
+	url := fmt.Sprintf("%s/threads/%s/runs", assistant.BaseURL, threadId)
+
+	return sendStream(url, runner)
+}
+
+// SubmitToolOutputsStream submits the outputs of the tool calls and streams
+// the events of the run as it resumes.
+func SubmitToolOutputsStream(threadID, runID string, outputs []ToolOutput) (*Stream, error) {
+	url := fmt.Sprintf("%s/threads/%s/runs/%s/submit_tool_outputs", assistant.BaseURL, threadID, runID)
+
+	return sendStream(url, map[string]interface{}{"tool_outputs": outputs, "stream": true})
+}
+
+func sendStream(url string, body interface{}) (*Stream, error) {
+	bodyJSON, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(bodyJSON))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := assistant.DoStream(req)
+	if err != nil {
+		return nil, err
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	return &Stream{body: resp.Body, scanner: scanner}, nil
+}
+
+// Next reads the next event. It returns false at the end of the stream,
+// after the done event, or on error.
+func (s *Stream) Next() bool {
+	if s.err != nil {
+		return false
+	}
+
+	var event Event
+	var data []string
+	for s.scanner.Scan() {
+		line := s.scanner.Text()
+		switch {
+		case line == "":
+			if event.Event == "" && len(data) == 0 {
+				continue
+			}
+			event.Data = json.RawMessage(strings.Join(data, "\n"))
+			return s.dispatch(event)
+		case strings.HasPrefix(line, "event:"):
+			event.Event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data = append(data, strings.TrimSpace(strings.TrimPrefix(line, "data:")))
+		}
+	}
+	if err := s.scanner.Err(); err != nil {
+		s.err = err
+		return false
+	}
+	if event.Event != "" || len(data) > 0 {
+		event.Data = json.RawMessage(strings.Join(data, "\n"))
+		return s.dispatch(event)
+	}
+	return false
+}
+
+func (s *Stream) dispatch(event Event) bool {
+	switch event.Event {
+	case EventDone:
+		return false
+	case EventError:
+		var errResp struct {
+			Message string `json:"message"`
+			Type    string `json:"type"`
+			Code    string `json:"code"`
+			Error   *struct {
+				Message string `json:"message"`
+				Type    string `json:"type"`
+				Code    string `json:"code"`
+			} `json:"error"`
+		}
+		apiErr := &assistant.APIError{StatusCode: http.StatusOK, Message: string(event.Data)}
+		if json.Unmarshal(event.Data, &errResp) == nil {
+			apiErr.Message, apiErr.Type, apiErr.Code = errResp.Message, errResp.Type, errResp.Code
+			if errResp.Error != nil {
+				apiErr.Message, apiErr.Type, apiErr.Code = errResp.Error.Message, errResp.Error.Type, errResp.Error.Code
+			}
+		}
+		s.err = apiErr
+		return false
+	}
+
+	s.event = event
+	return true
+}
+
+// Event returns the event read by the last call to Next.
+func (s *Stream) Event() Event {
+	return s.event
+}
+
+// Err returns the error that stopped the stream, if any.
+func (s *Stream) Err() error {
+	if errors.Is(s.err, io.EOF) {
+		return nil
+	}
+	return s.err
+}
+
+// Close closes the underlying connection.
+func (s *Stream) Close() error {
+	return s.body.Close()
+}
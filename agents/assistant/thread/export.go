@@ -0,0 +1,177 @@
+package thread
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/devalexandre/mylangchaingo/agents/assistant"
+	"github.com/devalexandre/mylangchaingo/agents/assistant/message"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// Transcript is the full history of a thread, oldest message first.
+type Transcript struct {
+	Thread   *Thread
+	Messages []message.Message
+}
+
+// ExportThread reads the thread and all the pages of its messages.
+func ExportThread(ctx context.Context, threadID string) (*Transcript, error) {
+	thread, err := RetrieveThread(threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := message.IterateMessages(threadID, assistant.WithOrder("asc"), assistant.WithLimit(100)).Collect(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list messages of thread %s: %w", threadID, err)
+	}
+
+	return &Transcript{Thread: thread, Messages: messages}, nil
+}
+
+// Search returns a transcript with only the messages whose metadata has all
+// the given keys and values.
+func (t *Transcript) Search(metadata map[string]string) *Transcript {
+	found := &Transcript{Thread: t.Thread}
+	for _, msg := range t.Messages {
+		if hasMetadata(msg.Metadata, metadata) {
+			found.Messages = append(found.Messages, msg)
+		}
+	}
+	return found
+}
+
+func hasMetadata(metadata, want map[string]string) bool {
+	for key, value := range want {
+		if got, ok := metadata[key]; !ok || got != value {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteJSONL writes one message per line, as returned by the API: all their
+// content parts, attachments, run IDs and metadata.
+func (t *Transcript) WriteJSONL(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	for _, msg := range t.Messages {
+		if err := encoder.Encode(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteMarkdown writes a transcript of the thread for people to read. The
+// citations are rendered as numbered footnotes, naming the files with resolve,
+// or RetrieveFileName when nil.
+func (t *Transcript) WriteMarkdown(w io.Writer, resolve message.FileNameResolver) error {
+	bw := bufio.NewWriter(w)
+
+	if t.Thread != nil {
+		fmt.Fprintf(bw, "# Thread %s\n\n", t.Thread.ID)
+		if t.Thread.CreatedAt != 0 {
+			fmt.Fprintf(bw, "- created: %s\n", formatTime(t.Thread.CreatedAt))
+		}
+		for _, key := range sortedKeys(t.Thread.Metadata) {
+			fmt.Fprintf(bw, "- %s: %s\n", key, t.Thread.Metadata[key])
+		}
+		fmt.Fprintln(bw)
+	}
+
+	for _, msg := range t.Messages {
+		heading := []string{msg.Role}
+		if msg.CreatedAt != 0 {
+			heading = append(heading, formatTime(msg.CreatedAt))
+		}
+		if msg.RunId != "" {
+			heading = append(heading, "run "+msg.RunId)
+		}
+		fmt.Fprintf(bw, "## %s\n\n", strings.Join(heading, " · "))
+
+		text, err := message.RenderWithCitations(msg.Content, resolve)
+		if err != nil {
+			return err
+		}
+		if text != "" {
+			fmt.Fprintf(bw, "%s\n\n", text)
+		}
+
+		for _, part := range msg.Content {
+			switch {
+			case part.Type == message.ContentTypeImageFile && part.ImageFile != nil:
+				fmt.Fprintf(bw, "![%[1]s](%[1]s)\n\n", part.ImageFile.FileID)
+			case part.Type == message.ContentTypeImageURL && part.ImageURL != nil:
+				fmt.Fprintf(bw, "![image](%s)\n\n", part.ImageURL.URL)
+			}
+		}
+
+		for _, attachment := range msg.Attachments {
+			var tools []string
+			for _, tool := range attachment.Tools {
+				tools = append(tools, string(tool.Type))
+			}
+			fmt.Fprintf(bw, "> attachment %s (%s)\n\n", attachment.FileID, strings.Join(tools, ", "))
+		}
+
+		for _, key := range sortedKeys(msg.Metadata) {
+			fmt.Fprintf(bw, "> %s: %s\n", key, msg.Metadata[key])
+		}
+		if len(msg.Metadata) > 0 {
+			fmt.Fprintln(bw)
+		}
+	}
+
+	return bw.Flush()
+}
+
+// MessageContents converts the messages to replay them into other models.
+// Images uploaded to OpenAI are replaced by a text naming their file, as
+// other models cannot read them.
+func (t *Transcript) MessageContents() []llms.MessageContent {
+	contents := make([]llms.MessageContent, 0, len(t.Messages))
+	for _, msg := range t.Messages {
+		role := llms.ChatMessageTypeHuman
+		if msg.Role == "assistant" {
+			role = llms.ChatMessageTypeAI
+		}
+
+		content := llms.MessageContent{Role: role}
+		for _, part := range msg.Content {
+			switch {
+			case part.Type == message.ContentTypeText && part.Text != nil:
+				content.Parts = append(content.Parts, llms.TextContent{Text: part.Text.Value})
+			case part.Type == message.ContentTypeRefusal:
+				content.Parts = append(content.Parts, llms.TextContent{Text: part.Refusal})
+			case part.Type == message.ContentTypeImageURL && part.ImageURL != nil:
+				content.Parts = append(content.Parts, llms.ImageURLContent{URL: part.ImageURL.URL, Detail: part.ImageURL.Detail})
+			case part.Type == message.ContentTypeImageFile && part.ImageFile != nil:
+				content.Parts = append(content.Parts, llms.TextContent{Text: fmt.Sprintf("[image %s]", part.ImageFile.FileID)})
+			}
+		}
+		if len(content.Parts) > 0 {
+			contents = append(contents, content)
+		}
+	}
+	return contents
+}
+
+func formatTime(unix int) string {
+	return time.Unix(int64(unix), 0).UTC().Format(time.RFC3339)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
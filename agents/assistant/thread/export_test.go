@@ -0,0 +1,110 @@
+package thread
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/devalexandre/mylangchaingo/agents/assistant"
+	"github.com/devalexandre/mylangchaingo/agents/assistant/message"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+func TestExportThread(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/threads/thread_1":
+			_, _ = io.WriteString(w, `{"id":"thread_1","created_at":1714557600,"metadata":{"customer":"42"}}`)
+		case "/threads/thread_1/messages":
+			assert.Equal(t, "asc", r.URL.Query().Get("order"))
+			if r.URL.Query().Get("after") == "" {
+				_, _ = io.WriteString(w, `{"data":[
+					{"id":"msg_1","created_at":1714557600,"role":"user","content":[
+						{"type":"text","text":{"value":"Summarize the report"}},
+						{"type":"image_url","image_url":{"url":"https://example.com/a.png"}}],
+					 "attachments":[{"file_id":"file_report","tools":[{"type":"file_search"}]}],"metadata":{"channel":"web"}}],
+					"has_more":true,"last_id":"msg_1"}`)
+				return
+			}
+			_, _ = io.WriteString(w, `{"data":[
+				{"id":"msg_2","created_at":1714557660,"role":"assistant","run_id":"run_1","content":[
+					{"type":"text","text":{"value":"Revenue grew【4:0†source】","annotations":[
+						{"type":"file_citation","text":"【4:0†source】","file_citation":{"file_id":"file_report"}}]}},
+					{"type":"image_file","image_file":{"file_id":"file_chart"}}]}]}`)
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	baseURL := assistant.BaseURL
+	assistant.BaseURL = srv.URL
+	defer func() { assistant.BaseURL = baseURL }()
+
+	transcript, err := ExportThread(context.Background(), "thread_1")
+	require.NoError(t, err)
+	require.Len(t, transcript.Messages, 2)
+
+	var jsonl bytes.Buffer
+	require.NoError(t, transcript.WriteJSONL(&jsonl))
+	scanner := bufio.NewScanner(&jsonl)
+	var lines []message.Message
+	for scanner.Scan() {
+		var msg message.Message
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &msg))
+		lines = append(lines, msg)
+	}
+	assert.Equal(t, transcript.Messages, lines)
+
+	var markdown bytes.Buffer
+	resolve := func(fileID string) (string, error) { return "report.pdf", nil }
+	require.NoError(t, transcript.WriteMarkdown(&markdown, resolve))
+	assert.Equal(t, `# Thread thread_1
+
+- created: 2024-05-01T10:00:00Z
+- customer: 42
+
+## user · 2024-05-01T10:00:00Z
+
+Summarize the report
+
+![image](https://example.com/a.png)
+
+> attachment file_report (file_search)
+
+> channel: web
+
+## assistant · 2024-05-01T10:01:00Z · run run_1
+
+Revenue grew[1]
+
+[1] report.pdf
+
+![file_chart](file_chart)
+
+`, markdown.String())
+
+	assert.Equal(t, []llms.MessageContent{
+		{Role: llms.ChatMessageTypeHuman, Parts: []llms.ContentPart{
+			llms.TextContent{Text: "Summarize the report"},
+			llms.ImageURLContent{URL: "https://example.com/a.png"},
+		}},
+		{Role: llms.ChatMessageTypeAI, Parts: []llms.ContentPart{
+			llms.TextContent{Text: "Revenue grew【4:0†source】"},
+			llms.TextContent{Text: "[image file_chart]"},
+		}},
+	}, transcript.MessageContents())
+
+	found := transcript.Search(map[string]string{"channel": "web"})
+	require.Len(t, found.Messages, 1)
+	assert.Equal(t, "msg_1", found.Messages[0].ID)
+	assert.Empty(t, transcript.Search(map[string]string{"channel": "email"}).Messages)
+}
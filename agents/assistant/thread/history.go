@@ -0,0 +1,155 @@
+package thread
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/devalexandre/mylangchaingo/agents/assistant"
+	"github.com/devalexandre/mylangchaingo/agents/assistant/message"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ChatMessageTypeMetadata and ChatMessageRoleMetadata are the metadata keys
+// holding the langchaingo type, and the role of the generic messages, of the
+// messages a ChatMessageHistory stores, as threads only have user and
+// assistant messages.
+const (
+	ChatMessageTypeMetadata = "chat_message_type"
+	ChatMessageRoleMetadata = "chat_message_role"
+)
+
+// ChatMessageHistory is a langchaingo chat message history kept in a thread,
+// so chains and memories can persist their history in OpenAI.
+//
+// System and other messages are stored as user messages, with their type in
+// the ChatMessageTypeMetadata metadata.
+type ChatMessageHistory struct {
+	mu       sync.Mutex
+	threadID string
+}
+
+var _ schema.ChatMessageHistory = &ChatMessageHistory{}
+
+// NewChatMessageHistory stores the history in a new thread.
+func NewChatMessageHistory() (*ChatMessageHistory, error) {
+	thread, err := CreateThread()
+	if err != nil {
+		return nil, err
+	}
+
+	return ResumeChatMessageHistory(thread.ID), nil
+}
+
+// ResumeChatMessageHistory uses the messages of an existing thread as the history.
+func ResumeChatMessageHistory(threadID string) *ChatMessageHistory {
+	return &ChatMessageHistory{threadID: threadID}
+}
+
+// ThreadID returns the thread of the history, which changes when it is cleared.
+func (h *ChatMessageHistory) ThreadID() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.threadID
+}
+
+// AddUserMessage adds a user message to the thread.
+func (h *ChatMessageHistory) AddUserMessage(ctx context.Context, text string) error {
+	return h.AddMessage(ctx, llms.HumanChatMessage{Content: text})
+}
+
+// AddAIMessage adds an assistant message to the thread.
+func (h *ChatMessageHistory) AddAIMessage(ctx context.Context, text string) error {
+	return h.AddMessage(ctx, llms.AIChatMessage{Content: text})
+}
+
+// AddMessage adds a message to the thread. Empty messages are not stored.
+func (h *ChatMessageHistory) AddMessage(_ context.Context, msg llms.ChatMessage) error {
+	role := "user"
+	var opts []message.MessageOption
+	switch msg.GetType() {
+	case llms.ChatMessageTypeHuman:
+	case llms.ChatMessageTypeAI:
+		role = "assistant"
+	default:
+		metadata := map[string]string{ChatMessageTypeMetadata: string(msg.GetType())}
+		if generic, ok := msg.(llms.GenericChatMessage); ok {
+			metadata[ChatMessageRoleMetadata] = generic.Role
+		}
+		opts = append(opts, message.WithMetadata(metadata))
+	}
+
+	if _, err := message.CreateMessage(h.ThreadID(), role, msg.GetContent(), opts...); err != nil {
+		return fmt.Errorf("failed to add message: %w", err)
+	}
+	return nil
+}
+
+// Messages returns all the messages of the thread, oldest first.
+func (h *ChatMessageHistory) Messages(ctx context.Context) ([]llms.ChatMessage, error) {
+	messages, err := message.IterateMessages(h.ThreadID(), assistant.WithOrder("asc"), assistant.WithLimit(100)).Collect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]llms.ChatMessage, 0, len(messages))
+	for _, msg := range messages {
+		history = append(history, chatMessage(msg))
+	}
+	return history, nil
+}
+
+func chatMessage(msg message.Message) llms.ChatMessage {
+	text := msg.Content.Text()
+	if msg.Role == "assistant" {
+		return llms.AIChatMessage{Content: text}
+	}
+
+	switch messageType := llms.ChatMessageType(msg.Metadata[ChatMessageTypeMetadata]); messageType {
+	case "", llms.ChatMessageTypeHuman:
+		return llms.HumanChatMessage{Content: text}
+	case llms.ChatMessageTypeSystem:
+		return llms.SystemChatMessage{Content: text}
+	default:
+		role := msg.Metadata[ChatMessageRoleMetadata]
+		if role == "" {
+			role = string(messageType)
+		}
+		return llms.GenericChatMessage{Role: role, Content: text}
+	}
+}
+
+// Clear continues the history in a new thread and deletes the old one. The
+// new thread is created first, so the history stays usable when it fails.
+func (h *ChatMessageHistory) Clear(_ context.Context) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	thread, err := CreateThread()
+	if err != nil {
+		return err
+	}
+	oldID := h.threadID
+	h.threadID = thread.ID
+
+	if _, err := DeleteThread(oldID); err != nil {
+		return fmt.Errorf("failed to delete thread %s: %w", oldID, err)
+	}
+	return nil
+}
+
+// SetMessages replaces the history with messages, in a new thread.
+func (h *ChatMessageHistory) SetMessages(ctx context.Context, messages []llms.ChatMessage) error {
+	if err := h.Clear(ctx); err != nil {
+		return err
+	}
+
+	for _, msg := range messages {
+		if err := h.AddMessage(ctx, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package thread
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/devalexandre/mylangchaingo/agents/assistant"
+	"github.com/devalexandre/mylangchaingo/agents/assistant/message"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/memory"
+)
+
+// fakeThreads keeps the messages of the threads in memory.
+type fakeThreads struct {
+	t          *testing.T
+	created    int
+	threads    map[string][]message.Message
+	failCreate bool
+}
+
+func (f *fakeThreads) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/threads":
+		if f.failCreate {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		f.created++
+		id := fmt.Sprintf("thread_%d", f.created)
+		f.threads[id] = []message.Message{}
+		fmt.Fprintf(w, `{"id":%q}`, id)
+	case r.Method == http.MethodDelete && len(parts) == 2:
+		delete(f.threads, parts[1])
+		fmt.Fprintf(w, `{"id":%q,"deleted":true}`, parts[1])
+	case r.Method == http.MethodPost && len(parts) == 3 && parts[2] == "messages":
+		var msg message.Message
+		require.NoError(f.t, json.NewDecoder(r.Body).Decode(&msg))
+		msg.ID = fmt.Sprintf("msg_%d", len(f.threads[parts[1]]))
+		f.threads[parts[1]] = append(f.threads[parts[1]], msg)
+		_ = json.NewEncoder(w).Encode(msg)
+	case r.Method == http.MethodGet && len(parts) == 3 && parts[2] == "messages":
+		assert.Equal(f.t, "asc", r.URL.Query().Get("order"))
+		_ = json.NewEncoder(w).Encode(message.Response{Data: f.threads[parts[1]]})
+	default:
+		f.t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestChatMessageHistory(t *testing.T) {
+	api := &fakeThreads{t: t, threads: map[string][]message.Message{}}
+	srv := httptest.NewServer(api)
+	defer srv.Close()
+
+	baseURL := assistant.BaseURL
+	assistant.BaseURL = srv.URL
+	defer func() { assistant.BaseURL = baseURL }()
+
+	ctx := context.Background()
+	history, err := NewChatMessageHistory()
+	require.NoError(t, err)
+	assert.Equal(t, "thread_1", history.ThreadID())
+
+	require.NoError(t, history.AddMessage(ctx, llms.SystemChatMessage{Content: "Be brief."}))
+	require.NoError(t, history.AddUserMessage(ctx, "Hi"))
+	require.NoError(t, history.AddAIMessage(ctx, "Hello!"))
+	require.NoError(t, history.AddMessage(ctx, llms.GenericChatMessage{Role: "critic", Content: "Too short"}))
+
+	stored := api.threads["thread_1"]
+	require.Len(t, stored, 4)
+	assert.Equal(t, "user", stored[0].Role)
+	assert.Equal(t, map[string]string{ChatMessageTypeMetadata: "system"}, stored[0].Metadata)
+	assert.Equal(t, "assistant", stored[2].Role)
+
+	// a resumed history reads the same thread
+	messages, err := ResumeChatMessageHistory("thread_1").Messages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []llms.ChatMessage{
+		llms.SystemChatMessage{Content: "Be brief."},
+		llms.HumanChatMessage{Content: "Hi"},
+		llms.AIChatMessage{Content: "Hello!"},
+		llms.GenericChatMessage{Role: "critic", Content: "Too short"},
+	}, messages)
+
+	require.NoError(t, history.SetMessages(ctx, []llms.ChatMessage{llms.HumanChatMessage{Content: "Start over"}}))
+	assert.Equal(t, "thread_2", history.ThreadID())
+	assert.NotContains(t, api.threads, "thread_1")
+	messages, err = history.Messages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []llms.ChatMessage{llms.HumanChatMessage{Content: "Start over"}}, messages)
+
+	// langchaingo memories can use it
+	buffer := memory.NewConversationBuffer(memory.WithChatHistory(history))
+	require.NoError(t, buffer.SaveContext(ctx, map[string]any{"input": "2+2?"}, map[string]any{"output": "4"}))
+	vars, err := buffer.LoadMemoryVariables(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Human: Start over\nHuman: 2+2?\nAI: 4", vars["history"])
+}
+
+func TestChatMessageHistoryClearKeepsThreadWhenCreateFails(t *testing.T) {
+	api := &fakeThreads{t: t, threads: map[string][]message.Message{}}
+	srv := httptest.NewServer(api)
+	defer srv.Close()
+
+	baseURL := assistant.BaseURL
+	assistant.BaseURL = srv.URL
+	defer func() { assistant.BaseURL = baseURL }()
+
+	ctx := context.Background()
+	history, err := NewChatMessageHistory()
+	require.NoError(t, err)
+	require.NoError(t, history.AddUserMessage(ctx, "Hi"))
+
+	api.failCreate = true
+	require.Error(t, history.Clear(ctx))
+	assert.Equal(t, "thread_1", history.ThreadID())
+
+	// the old thread was not deleted, so the history still works
+	require.NoError(t, history.AddAIMessage(ctx, "Hello!"))
+	messages, err := history.Messages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []llms.ChatMessage{llms.HumanChatMessage{Content: "Hi"}, llms.AIChatMessage{Content: "Hello!"}}, messages)
+}
@@ -6,15 +6,46 @@ import (
 	"fmt"
 	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/tools"
-	"io/ioutil"
+	"io"
 	"net/http"
 	"os"
 	"regexp"
 	"strings"
 )
 
+// APIError is returned by Do when the API answers with a non 2xx status code.
+type APIError struct {
+	StatusCode int
+	Type       string
+	Code       string
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("API returned unexpected status code: %d", e.StatusCode)
+	}
+	return fmt.Sprintf("API returned unexpected status code: %d: %s", e.StatusCode, e.Message)
+}
+
+// Do sends the request with the OpenAI credentials and returns the response body.
+// Requests without a Content-Type are sent as JSON.
 func Do(req *http.Request) ([]byte, error) {
-	req.Header.Set("Content-Type", "application/json")
+	resp, err := DoStream(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return io.ReadAll(resp.Body)
+}
+
+// DoStream sends the request like Do but returns the response unread, so
+// server-sent events can be consumed as they arrive. The caller must close its body.
+func DoStream(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", os.Getenv("OPENAI_API_KEY")))
 	req.Header.Set("OpenAI-Beta", "assistants=v2")
 
@@ -23,9 +54,31 @@ func Do(req *http.Request) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	return ioutil.ReadAll(resp.Body)
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+		var errResp struct {
+			Error struct {
+				Message string `json:"message"`
+				Type    string `json:"type"`
+				Code    string `json:"code"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(body, &errResp) == nil {
+			apiErr.Message = errResp.Error.Message
+			apiErr.Type = errResp.Error.Type
+			apiErr.Code = errResp.Error.Code
+		}
+		return nil, apiErr
+	}
+
+	return resp, nil
 }
 
 func SubmitToolOutput(threadID, runID, toolCallID, output string) error {
@@ -0,0 +1,93 @@
+package vectorstore
+
+import "github.com/devalexandre/mylangchaingo/agents/assistant"
+
+const (
+	StatusInProgress = "in_progress"
+	StatusCompleted  = "completed"
+	StatusFailed     = "failed"
+	StatusCancelled  = "cancelled"
+	StatusExpired    = "expired"
+)
+
+type FileCounts struct {
+	InProgress int `json:"in_progress"`
+	Completed  int `json:"completed"`
+	Failed     int `json:"failed"`
+	Cancelled  int `json:"cancelled"`
+	Total      int `json:"total"`
+}
+
+type ExpiresAfter struct {
+	Anchor string `json:"anchor"` // only "last_active_at" is supported
+	Days   int    `json:"days"`
+}
+
+type VectorStore struct {
+	ID               string                      `json:"id,omitempty"`
+	Object           string                      `json:"object,omitempty"`
+	CreatedAt        int                         `json:"created_at,omitempty"`
+	Name             string                      `json:"name,omitempty"`
+	UsageBytes       int64                       `json:"usage_bytes,omitempty"`
+	FileCounts       *FileCounts                 `json:"file_counts,omitempty"`
+	Status           string                      `json:"status,omitempty"`
+	ExpiresAfter     *ExpiresAfter               `json:"expires_after,omitempty"`
+	ExpiresAt        *int                        `json:"expires_at,omitempty"`
+	LastActiveAt     *int                        `json:"last_active_at,omitempty"`
+	Metadata         map[string]string           `json:"metadata,omitempty"`
+	FileIDs          []string                    `json:"file_ids,omitempty"`
+	ChunkingStrategy *assistant.ChunkingStrategy `json:"chunking_strategy,omitempty"`
+}
+
+// GetID returns the ID of the vector store.
+func (v VectorStore) GetID() string {
+	return v.ID
+}
+
+type Response struct {
+	Object  string        `json:"object"`
+	Data    []VectorStore `json:"data"`
+	FirstId string        `json:"first_id,omitempty"`
+	LastId  string        `json:"last_id,omitempty"`
+	HasMore bool          `json:"has_more,omitempty"`
+}
+
+type LastError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// File is a file attached to a vector store.
+type File struct {
+	ID               string                      `json:"id,omitempty"`
+	Object           string                      `json:"object,omitempty"`
+	UsageBytes       int64                       `json:"usage_bytes,omitempty"`
+	CreatedAt        int                         `json:"created_at,omitempty"`
+	VectorStoreID    string                      `json:"vector_store_id,omitempty"`
+	Status           string                      `json:"status,omitempty"`
+	LastError        *LastError                  `json:"last_error,omitempty"`
+	ChunkingStrategy *assistant.ChunkingStrategy `json:"chunking_strategy,omitempty"`
+}
+
+// GetID returns the ID of the vector store file.
+func (f File) GetID() string {
+	return f.ID
+}
+
+type FileResponse struct {
+	Object  string `json:"object"`
+	Data    []File `json:"data"`
+	FirstId string `json:"first_id,omitempty"`
+	LastId  string `json:"last_id,omitempty"`
+	HasMore bool   `json:"has_more,omitempty"`
+}
+
+// FileBatch is a batch of files being attached to a vector store.
+type FileBatch struct {
+	ID            string     `json:"id"`
+	Object        string     `json:"object"`
+	CreatedAt     int        `json:"created_at"`
+	VectorStoreID string     `json:"vector_store_id"`
+	Status        string     `json:"status"`
+	FileCounts    FileCounts `json:"file_counts"`
+}
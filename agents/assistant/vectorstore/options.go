@@ -0,0 +1,41 @@
+package vectorstore
+
+import "github.com/devalexandre/mylangchaingo/agents/assistant"
+
+// Option configures a vector store.
+type Option func(*VectorStore)
+
+// WithName sets the name of the vector store.
+func WithName(name string) Option {
+	return func(v *VectorStore) {
+		v.Name = name
+	}
+}
+
+// WithFileIDs sets the files indexed when the vector store is created.
+func WithFileIDs(fileIDs []string) Option {
+	return func(v *VectorStore) {
+		v.FileIDs = fileIDs
+	}
+}
+
+// WithChunkingStrategy sets how the files given with WithFileIDs are chunked.
+func WithChunkingStrategy(strategy *assistant.ChunkingStrategy) Option {
+	return func(v *VectorStore) {
+		v.ChunkingStrategy = strategy
+	}
+}
+
+// WithExpiresAfter expires the vector store the given days after it was last active.
+func WithExpiresAfter(days int) Option {
+	return func(v *VectorStore) {
+		v.ExpiresAfter = &ExpiresAfter{Anchor: "last_active_at", Days: days}
+	}
+}
+
+// WithMetadata sets the metadata of the vector store.
+func WithMetadata(metadata map[string]string) Option {
+	return func(v *VectorStore) {
+		v.Metadata = metadata
+	}
+}
@@ -0,0 +1,235 @@
+package vectorstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/devalexandre/mylangchaingo/agents/assistant"
+	"github.com/devalexandre/mylangchaingo/agents/assistant/file"
+)
+
+const _defaultPollInterval = time.Second
+
+// ErrFileBatchFailed is returned by WaitFileBatch when the batch did not complete.
+var ErrFileBatchFailed = errors.New("vector store file batch did not complete")
+
+// CreateVectorStore creates a new vector store.
+func CreateVectorStore(opts ...Option) (*VectorStore, error) {
+	vectorStore := &VectorStore{}
+	for _, opt := range opts {
+		opt(vectorStore)
+	}
+
+	var response VectorStore
+	if err := request("POST", "/vector_stores", vectorStore, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ListVectorStores returns all the vector stores, reading every page.
+func ListVectorStores(opts ...assistant.ListOption) ([]VectorStore, error) {
+	return IterateVectorStores(opts...).Collect(context.Background())
+}
+
+// IterateVectorStores returns an iterator over all the vector stores.
+func IterateVectorStores(opts ...assistant.ListOption) *assistant.Iterator[VectorStore] {
+	return assistant.NewIterator(func(opts ...assistant.ListOption) (*assistant.Page[VectorStore], error) {
+		var response Response
+		if err := request("GET", "/vector_stores"+assistant.ListQuery(opts...), nil, &response); err != nil {
+			return nil, err
+		}
+		return &assistant.Page[VectorStore]{Data: response.Data, HasMore: response.HasMore, LastID: response.LastId}, nil
+	}, opts...)
+}
+
+// RetrieveVectorStore returns a vector store, including its status and file counts.
+func RetrieveVectorStore(vectorStoreID string) (*VectorStore, error) {
+	var response VectorStore
+	if err := request("GET", "/vector_stores/"+vectorStoreID, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Delete a vector store. The files themselves are not deleted.
+func DeleteVectorStore(vectorStoreID string) (*assistant.AssistantResponse, error) {
+	var response assistant.AssistantResponse
+	if err := request("DELETE", "/vector_stores/"+vectorStoreID, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// AddFile attaches an uploaded file to a vector store. A nil strategy uses the auto strategy.
+func AddFile(vectorStoreID, fileID string, strategy *assistant.ChunkingStrategy) (*File, error) {
+	body := map[string]interface{}{"file_id": fileID}
+	if strategy != nil {
+		body["chunking_strategy"] = strategy
+	}
+
+	var response File
+	if err := request("POST", fmt.Sprintf("/vector_stores/%s/files", vectorStoreID), body, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ListFiles returns all the files attached to a vector store, reading every page.
+func ListFiles(vectorStoreID string, opts ...assistant.ListOption) ([]File, error) {
+	return IterateFiles(vectorStoreID, opts...).Collect(context.Background())
+}
+
+// IterateFiles returns an iterator over the files attached to a vector store.
+func IterateFiles(vectorStoreID string, opts ...assistant.ListOption) *assistant.Iterator[File] {
+	return assistant.NewIterator(func(opts ...assistant.ListOption) (*assistant.Page[File], error) {
+		var response FileResponse
+		path := fmt.Sprintf("/vector_stores/%s/files%s", vectorStoreID, assistant.ListQuery(opts...))
+		if err := request("GET", path, nil, &response); err != nil {
+			return nil, err
+		}
+		return &assistant.Page[File]{Data: response.Data, HasMore: response.HasMore, LastID: response.LastId}, nil
+	}, opts...)
+}
+
+// RemoveFile detaches a file from a vector store. The file itself is not deleted.
+func RemoveFile(vectorStoreID, fileID string) (*assistant.AssistantResponse, error) {
+	var response assistant.AssistantResponse
+	if err := request("DELETE", fmt.Sprintf("/vector_stores/%s/files/%s", vectorStoreID, fileID), nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// CreateFileBatch attaches several uploaded files to a vector store at once.
+// A nil strategy uses the auto strategy.
+func CreateFileBatch(vectorStoreID string, fileIDs []string, strategy *assistant.ChunkingStrategy) (*FileBatch, error) {
+	body := map[string]interface{}{"file_ids": fileIDs}
+	if strategy != nil {
+		body["chunking_strategy"] = strategy
+	}
+
+	var response FileBatch
+	if err := request("POST", fmt.Sprintf("/vector_stores/%s/file_batches", vectorStoreID), body, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// RetrieveFileBatch returns the status of a file batch.
+func RetrieveFileBatch(vectorStoreID, batchID string) (*FileBatch, error) {
+	var response FileBatch
+	if err := request("GET", fmt.Sprintf("/vector_stores/%s/file_batches/%s", vectorStoreID, batchID), nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// CancelFileBatch cancels the processing of a file batch.
+func CancelFileBatch(vectorStoreID, batchID string) (*FileBatch, error) {
+	var response FileBatch
+	if err := request("POST", fmt.Sprintf("/vector_stores/%s/file_batches/%s/cancel", vectorStoreID, batchID), nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// WaitFileBatch polls a file batch every interval until its files are indexed.
+// It returns ErrFileBatchFailed, along with the batch, when the batch failed
+// or was cancelled, or when some of its files could not be indexed.
+func WaitFileBatch(ctx context.Context, vectorStoreID, batchID string, interval time.Duration) (*FileBatch, error) {
+	if interval <= 0 {
+		interval = _defaultPollInterval
+	}
+
+	for {
+		batch, err := RetrieveFileBatch(vectorStoreID, batchID)
+		if err != nil {
+			return nil, err
+		}
+
+		switch batch.Status {
+		case StatusCompleted:
+			if batch.FileCounts.Failed > 0 {
+				return batch, fmt.Errorf("%w: %d of %d files failed", ErrFileBatchFailed, batch.FileCounts.Failed, batch.FileCounts.Total)
+			}
+			return batch, nil
+		case StatusFailed, StatusCancelled:
+			return batch, fmt.Errorf("%w: status %s", ErrFileBatchFailed, batch.Status)
+		}
+
+		select {
+		case <-ctx.Done():
+			return batch, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// UploadFiles uploads the files, attaches them to the vector store in a
+// single batch and waits until they are indexed. files maps file names to their content.
+//
+// When an upload or the batch creation fails, the files already uploaded are
+// deleted; the error lists the ones that could not be.
+func UploadFiles(ctx context.Context, vectorStoreID string, files map[string]io.Reader, strategy *assistant.ChunkingStrategy) (*FileBatch, error) {
+	fileIDs := make([]string, 0, len(files))
+	for name, content := range files {
+		uploaded, err := file.UploadFile(name, content, file.PurposeAssistants)
+		if err != nil {
+			return nil, deleteUploaded(fileIDs, err)
+		}
+		fileIDs = append(fileIDs, uploaded.ID)
+	}
+
+	batch, err := CreateFileBatch(vectorStoreID, fileIDs, strategy)
+	if err != nil {
+		return nil, deleteUploaded(fileIDs, fmt.Errorf("failed to create file batch: %w", err))
+	}
+
+	return WaitFileBatch(ctx, vectorStoreID, batch.ID, _defaultPollInterval)
+}
+
+// deleteUploaded deletes the files uploaded before err happened.
+func deleteUploaded(fileIDs []string, err error) error {
+	var left []string
+	for _, fileID := range fileIDs {
+		if _, deleteErr := file.DeleteFile(fileID); deleteErr != nil {
+			left = append(left, fileID)
+		}
+	}
+	if len(left) > 0 {
+		return fmt.Errorf("%w (uploaded files %s were not deleted)", err, strings.Join(left, ", "))
+	}
+	return err
+}
+
+// request sends body as JSON to the given path and decodes the response into out.
+func request(method, path string, body interface{}, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		bodyJSON, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewBuffer(bodyJSON)
+	}
+
+	req, err := http.NewRequest(method, assistant.BaseURL+path, reader)
+	if err != nil {
+		return err
+	}
+
+	respBody, err := assistant.Do(req)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(respBody, out)
+}
@@ -0,0 +1,133 @@
+package vectorstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/devalexandre/mylangchaingo/agents/assistant"
+	"github.com/devalexandre/mylangchaingo/internal/assistanttest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateFileBatchWithStaticChunking(t *testing.T) {
+	assistanttest.UseServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/vector_stores/vs_1/file_batches", r.URL.Path)
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"file_ids":["file_1","file_2"],"chunking_strategy":{
+			"type":"static","static":{"max_chunk_size_tokens":400,"chunk_overlap_tokens":100}}}`, string(body))
+		_, _ = io.WriteString(w, `{"id":"vsfb_1","vector_store_id":"vs_1","status":"in_progress","file_counts":{"in_progress":2,"total":2}}`)
+	})
+
+	batch, err := CreateFileBatch("vs_1", []string{"file_1", "file_2"}, assistant.NewStaticChunkingStrategy(400, 100))
+	require.NoError(t, err)
+	assert.Equal(t, "vsfb_1", batch.ID)
+	assert.Equal(t, 2, batch.FileCounts.Total)
+}
+
+func TestWaitFileBatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []string
+		err      error
+	}{
+		{
+			name: "completed",
+			statuses: []string{
+				`{"id":"vsfb_1","status":"in_progress","file_counts":{"in_progress":1,"total":1}}`,
+				`{"id":"vsfb_1","status":"completed","file_counts":{"completed":1,"total":1}}`,
+			},
+		},
+		{
+			name:     "failed files",
+			statuses: []string{`{"id":"vsfb_1","status":"completed","file_counts":{"completed":1,"failed":1,"total":2}}`},
+			err:      ErrFileBatchFailed,
+		},
+		{
+			name:     "cancelled",
+			statuses: []string{`{"id":"vsfb_1","status":"cancelled"}`},
+			err:      ErrFileBatchFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			polls := 0
+			assistanttest.UseServer(t, func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/vector_stores/vs_1/file_batches/vsfb_1", r.URL.Path)
+				_, _ = io.WriteString(w, tt.statuses[min(polls, len(tt.statuses)-1)])
+				polls++
+			})
+
+			batch, err := WaitFileBatch(context.Background(), "vs_1", "vsfb_1", time.Millisecond)
+			require.ErrorIs(t, err, tt.err)
+			require.NotNil(t, batch)
+			assert.Equal(t, len(tt.statuses), polls)
+		})
+	}
+}
+
+func TestWaitFileBatchCancelled(t *testing.T) {
+	assistanttest.UseServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `{"id":"vsfb_1","status":"in_progress"}`)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	batch, err := WaitFileBatch(ctx, "vs_1", "vsfb_1", time.Millisecond)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, StatusInProgress, batch.Status)
+}
+
+func TestUploadFilesDeletesUploadedFilesOnFailure(t *testing.T) {
+	var mu sync.Mutex
+	var uploads int
+	var deleted []string
+	assistanttest.UseServer(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/files":
+			uploads++
+			if uploads == 2 {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = io.WriteString(w, `{"error":{"message":"unsupported file"}}`)
+				return
+			}
+			fmt.Fprintf(w, `{"id":"file_%d"}`, uploads)
+		case r.Method == http.MethodDelete:
+			deleted = append(deleted, strings.TrimPrefix(r.URL.Path, "/files/"))
+			_ = json.NewEncoder(w).Encode(map[string]any{"deleted": true})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	_, err := UploadFiles(context.Background(), "vs_1", map[string]io.Reader{
+		"a.txt": strings.NewReader("a"),
+		"b.txt": strings.NewReader("b"),
+	}, nil)
+	require.ErrorContains(t, err, "unsupported file")
+	assert.Equal(t, []string{"file_1"}, deleted)
+}
+
+func TestAddFileWithAutoChunking(t *testing.T) {
+	assistanttest.UseServer(t, func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"file_id":"file_1","chunking_strategy":{"type":"auto"}}`, string(body))
+		_, _ = io.WriteString(w, `{"id":"file_1","vector_store_id":"vs_1","status":"in_progress"}`)
+	})
+
+	_, err := AddFile("vs_1", "file_1", assistant.NewAutoChunkingStrategy())
+	require.NoError(t, err)
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/devalexandre/mylangchaingo/agents/assistant"
+)
+
+func (c *cli) assistants(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return c.usage()
+	}
+
+	command, args := args[0], args[1:]
+	switch command {
+	case "list":
+		return c.listAssistants(ctx, args)
+	case "get":
+		return c.getAssistant(args)
+	case "create":
+		return c.createAssistant(args)
+	case "update":
+		return c.updateAssistant(args)
+	case "delete":
+		return c.deleteAssistants(args)
+	case "sync":
+		return c.syncAssistants(ctx, args)
+	}
+	return c.usage()
+}
+
+func (c *cli) listAssistants(ctx context.Context, args []string) error {
+	fs := c.flags("assistants list")
+	limit := fs.Int("limit", 0, "maximum number of assistants, 0 for all")
+	asJSON := fs.Bool("json", false, "print the assistants as JSON")
+	if err := c.parse(fs, args, 0); err != nil {
+		return err
+	}
+
+	var list []assistant.Assistant
+	it := assistant.IterateAssistants(assistant.WithLimit(100))
+	for (*limit == 0 || len(list) < *limit) && it.Next(ctx) {
+		list = append(list, it.Current())
+	}
+	if err := it.Err(); err != nil {
+		return err
+	}
+
+	if *asJSON {
+		return c.printJSON(list)
+	}
+
+	w := tabwriter.NewWriter(c.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tMODEL\tTOOLS")
+	for _, a := range list {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", a.ID, a.Name, a.Model, strings.Join(toolNames(a), ","))
+	}
+	return w.Flush()
+}
+
+func (c *cli) getAssistant(args []string) error {
+	fs := c.flags("assistants get")
+	if err := c.parse(fs, args, 1); err != nil {
+		return err
+	}
+
+	a, err := (&assistant.Assistant{ID: fs.Arg(0)}).RetrieveAssistant()
+	if err != nil {
+		return err
+	}
+	return c.printJSON(a)
+}
+
+func (c *cli) createAssistant(args []string) error {
+	fs := c.flags("assistants create")
+	file := fs.String("f", "", "YAML or JSON definition of the assistant")
+	fields := assistantFlags(fs)
+	if err := c.parse(fs, args, 0); err != nil {
+		return err
+	}
+
+	var a *assistant.Assistant
+	if *file != "" {
+		definition, err := assistant.LoadDefinition(*file)
+		if err != nil {
+			return err
+		}
+		if a, err = definition.Assistant(c.tools); err != nil {
+			return err
+		}
+	} else {
+		if fields.name == "" || fields.model == "" {
+			return c.usage()
+		}
+		a = &assistant.Assistant{}
+		if err := c.applyFields(fs, fields, a); err != nil {
+			return err
+		}
+	}
+
+	// NewAssistant applies the options to a new assistant before creating it
+	created, err := assistant.NewAssistant(func(created *assistant.Assistant) { *created = *a })
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(c.stdout, created.ID)
+	return nil
+}
+
+func (c *cli) updateAssistant(args []string) error {
+	fs := c.flags("assistants update")
+	fields := assistantFlags(fs)
+	if err := c.parse(fs, args, 1); err != nil {
+		return err
+	}
+
+	existing, err := (&assistant.Assistant{ID: fs.Arg(0)}).RetrieveAssistant()
+	if err != nil {
+		return err
+	}
+
+	update := *existing
+	update.ID = ""
+	if err := c.applyFields(fs, fields, &update); err != nil {
+		return err
+	}
+
+	updated, err := existing.UpdateAssistant(update)
+	if err != nil {
+		return err
+	}
+	return c.printJSON(updated)
+}
+
+func (c *cli) deleteAssistants(args []string) error {
+	fs := c.flags("assistants delete")
+	if err := c.parse(fs, args, -1); err != nil {
+		return err
+	}
+
+	for _, id := range fs.Args() {
+		if _, err := (&assistant.Assistant{ID: id}).DeleteAssistant(); err != nil {
+			return fmt.Errorf("failed to delete %s: %w", id, err)
+		}
+		fmt.Fprintln(c.stdout, "deleted", id)
+	}
+	return nil
+}
+
+func (c *cli) syncAssistants(ctx context.Context, args []string) error {
+	fs := c.flags("assistants sync")
+	if err := c.parse(fs, args, -1); err != nil {
+		return err
+	}
+
+	for _, path := range fs.Args() {
+		definition, err := assistant.LoadDefinition(path)
+		if err != nil {
+			return err
+		}
+		result, err := assistant.Sync(ctx, definition, c.tools)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(c.stdout, "%s %s %s", result.Action, result.Assistant.ID, definition.Name)
+		if len(result.Changes) > 0 {
+			fmt.Fprintf(c.stdout, " (%s)", strings.Join(result.Changes, ", "))
+		}
+		fmt.Fprintln(c.stdout)
+	}
+	return nil
+}
+
+// fields are the flags shared by create and update.
+type fields struct {
+	name, model, description, instructions, tools string
+}
+
+func assistantFlags(fs *flag.FlagSet) *fields {
+	f := &fields{}
+	fs.StringVar(&f.name, "name", "", "name of the assistant")
+	fs.StringVar(&f.model, "model", "", "model of the assistant")
+	fs.StringVar(&f.description, "description", "", "description of the assistant")
+	fs.StringVar(&f.instructions, "instructions", "", "instructions of the assistant")
+	fs.StringVar(&f.tools, "tools", "", "comma separated local tools of the assistant")
+	return f
+}
+
+// applyFields sets the fields given on the command line.
+func (c *cli) applyFields(fs *flag.FlagSet, f *fields, a *assistant.Assistant) error {
+	var err error
+	fs.Visit(func(set *flag.Flag) {
+		switch set.Name {
+		case "name":
+			a.Name = f.name
+		case "model":
+			a.Model = f.model
+		case "description":
+			a.Description = f.description
+		case "instructions":
+			a.Instructions = f.instructions
+		case "tools":
+			selected, selectErr := c.selectTools(f.tools)
+			if selectErr != nil {
+				err = selectErr
+				return
+			}
+			assistant.WithTools(selected)(a)
+		}
+	})
+	return err
+}
+
+func toolNames(a assistant.Assistant) []string {
+	if a.Tools == nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(*a.Tools))
+	for _, tool := range *a.Tools {
+		if tool.Function != nil {
+			names = append(names, tool.Function.Name)
+		} else {
+			names = append(names, tool.Type)
+		}
+	}
+	return names
+}
+
+func (c *cli) printJSON(v any) error {
+	encoder := json.NewEncoder(c.stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/devalexandre/mylangchaingo/agents/assistant"
+	"github.com/devalexandre/mylangchaingo/agents/assistant/executor"
+)
+
+// chat talks with an assistant, one line of input per message, running its
+// tools locally. The tool calls are printed as they happen.
+func (c *cli) chat(ctx context.Context, args []string) error {
+	fs := c.flags("chat")
+	threadID := fs.String("thread", "", "thread to continue, a new one by default")
+	toolList := fs.String("tools", strings.Join(c.toolNames(), ","), "comma separated local tools")
+	if err := c.parse(fs, args, 1); err != nil {
+		return err
+	}
+
+	selected, err := c.selectTools(*toolList)
+	if err != nil {
+		return err
+	}
+
+	events := make(chan executor.Event)
+	ae := executor.NewAgentExecutor(&assistant.Assistant{ID: fs.Arg(0)},
+		executor.WithTools(selected),
+		executor.WithEvents(events),
+	)
+	go c.printEvents(events)
+	defer close(events)
+
+	var session *executor.Session
+	if *threadID != "" {
+		session = ae.ResumeSession(*threadID)
+	} else if session, err = ae.NewSession(); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.stderr, "thread %s, empty line or Ctrl-D to quit\n", session.ThreadID)
+
+	scanner := bufio.NewScanner(c.stdin)
+	for {
+		fmt.Fprint(c.stdout, "> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(c.stdout)
+			return scanner.Err()
+		}
+		input := strings.TrimSpace(scanner.Text())
+		if input == "" {
+			return nil
+		}
+
+		result, err := session.Send(ctx, input)
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return nil
+			}
+			fmt.Fprintln(c.stderr, "error:", err)
+		}
+		if result != nil {
+			fmt.Fprintf(c.stdout, "%s\n\n", result.Output)
+		}
+	}
+}
+
+func (c *cli) printEvents(events <-chan executor.Event) {
+	for event := range events {
+		switch event.Type {
+		case executor.EventToolStart:
+			fmt.Fprintf(c.stderr, "  -> %s(%s)\n", event.Tool.Name, event.Tool.Arguments)
+		case executor.EventToolEnd:
+			fmt.Fprintf(c.stderr, "  <- %s\n", truncate(event.Output, 200))
+		}
+	}
+}
+
+func truncate(s string, max int) string {
+	runes := []rune(strings.ReplaceAll(s, "\n", " "))
+	if len(runes) <= max {
+		return string(runes)
+	}
+	return string(runes[:max]) + "..."
+}
@@ -0,0 +1,150 @@
+// Command assistant manages the assistants, threads and runs of the
+// Assistants API, and chats with an assistant using local tools.
+//
+// Usage:
+//
+//	assistant assistants list|get|create|update|delete|sync
+//	assistant threads messages|export THREAD_ID
+//	assistant runs tail THREAD_ID RUN_ID
+//	assistant chat [-thread THREAD_ID] [-tools calculator,scraper] ASSISTANT_ID
+//
+// The API key is read from OPENAI_API_KEY.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+
+	"github.com/devalexandre/mylangchaingo/agents/assistant"
+	"github.com/devalexandre/mylangchaingo/tools/scraper/goquery"
+	"github.com/tmc/langchaingo/tools"
+)
+
+const usage = `usage: assistant <command> [arguments]
+
+commands:
+  assistants list [-limit N] [-json]
+  assistants get ASSISTANT_ID
+  assistants create (-f FILE | -name NAME -model MODEL [-instructions TEXT] [-tools a,b])
+  assistants update [-name NAME] [-model MODEL] [-instructions TEXT] [-tools a,b] ASSISTANT_ID
+  assistants delete ASSISTANT_ID...
+  assistants sync FILE...
+  threads messages [-json] THREAD_ID
+  threads export [-format markdown|jsonl] [-metadata KEY=VALUE] THREAD_ID
+  runs tail [-interval D] THREAD_ID RUN_ID
+  chat [-thread THREAD_ID] [-tools a,b] ASSISTANT_ID
+
+tools: %s
+`
+
+// errUsage is returned for invalid command lines, after printing the usage.
+var errUsage = errors.New("invalid usage")
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	cli := &cli{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr, tools: localTools()}
+	if err := cli.run(ctx, os.Args[1:]); err != nil {
+		if !errors.Is(err, errUsage) {
+			fmt.Fprintln(os.Stderr, "error:", err)
+		}
+		os.Exit(1)
+	}
+}
+
+type cli struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	// tools are the local tools the assistants can use, by name
+	tools assistant.ToolRegistry
+}
+
+func (c *cli) run(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return c.usage()
+	}
+
+	command, args := args[0], args[1:]
+	switch command {
+	case "assistants":
+		return c.assistants(ctx, args)
+	case "threads":
+		return c.threads(ctx, args)
+	case "runs":
+		return c.runs(ctx, args)
+	case "chat":
+		return c.chat(ctx, args)
+	case "help", "-h", "-help", "--help":
+		_ = c.usage()
+		return nil
+	}
+	return c.usage()
+}
+
+func (c *cli) usage() error {
+	fmt.Fprintf(c.stderr, usage, strings.Join(c.toolNames(), ", "))
+	return errUsage
+}
+
+// flags returns a flag set for a subcommand that prints the usage on errors.
+func (c *cli) flags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	fs.Usage = func() { _ = c.usage() }
+	return fs
+}
+
+// parse parses the flags of a subcommand and checks it got nargs arguments,
+// or at least one when nargs is negative.
+func (c *cli) parse(fs *flag.FlagSet, args []string, nargs int) error {
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	if (nargs >= 0 && fs.NArg() != nargs) || (nargs < 0 && fs.NArg() == 0) {
+		return c.usage()
+	}
+	return nil
+}
+
+func (c *cli) toolNames() []string {
+	names := make([]string, 0, len(c.tools))
+	for name := range c.tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// selectTools returns the local tools named in a comma separated list.
+func (c *cli) selectTools(list string) ([]tools.Tool, error) {
+	var selected []tools.Tool
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		tool, ok := c.tools[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown tool %q, the tools are: %s", name, strings.Join(c.toolNames(), ", "))
+		}
+		selected = append(selected, tool)
+	}
+	return selected, nil
+}
+
+func localTools() assistant.ToolRegistry {
+	registry := assistant.ToolRegistry{"calculator": tools.Calculator{}}
+	if scraper, err := goquery.New(); err == nil {
+		registry["scraper"] = scraper
+	}
+	return registry
+}
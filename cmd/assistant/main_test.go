@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/devalexandre/mylangchaingo/agents/assistant"
+	"github.com/devalexandre/mylangchaingo/internal/assistanttest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/tools"
+)
+
+func newTestCLI(stdin string) (*cli, *bytes.Buffer, *bytes.Buffer) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	return &cli{
+		stdin:  strings.NewReader(stdin),
+		stdout: stdout,
+		stderr: stderr,
+		tools:  assistant.ToolRegistry{"calculator": tools.Calculator{}},
+	}, stdout, stderr
+}
+
+func TestAssistantsList(t *testing.T) {
+	assistanttest.UseServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/assistants", r.URL.Path)
+		_, _ = io.WriteString(w, `{"data":[{"id":"asst_1","name":"Calculator","model":"gpt-4o",
+			"tools":[{"type":"function","function":{"name":"calculator"}},{"type":"code_interpreter"}]}]}`)
+	})
+
+	c, stdout, _ := newTestCLI("")
+	require.NoError(t, c.run(context.Background(), []string{"assistants", "list"}))
+	assert.Equal(t, "ID      NAME        MODEL   TOOLS\nasst_1  Calculator  gpt-4o  calculator,code_interpreter\n", stdout.String())
+}
+
+func TestThreadsMessages(t *testing.T) {
+	assistanttest.UseServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/threads/thread_1/messages", r.URL.Path)
+		assert.Equal(t, "asc", r.URL.Query().Get("order"))
+		_, _ = io.WriteString(w, `{"data":[
+			{"id":"msg_1","role":"user","content":"What is 2 + 2?"},
+			{"id":"msg_2","role":"assistant","content":[{"type":"text","text":{"value":"4","annotations":[]}}]}]}`)
+	})
+
+	c, stdout, _ := newTestCLI("")
+	require.NoError(t, c.run(context.Background(), []string{"threads", "messages", "thread_1"}))
+	assert.Contains(t, stdout.String(), "user:\nWhat is 2 + 2?\n")
+	assert.Contains(t, stdout.String(), "assistant:\n4\n")
+}
+
+func TestRunsTail(t *testing.T) {
+	polls := 0
+	assistanttest.UseServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/threads/thread_1/runs/run_1":
+			polls++
+			if polls == 1 {
+				_, _ = io.WriteString(w, `{"id":"run_1","status":"in_progress"}`)
+				return
+			}
+			_, _ = io.WriteString(w, `{"id":"run_1","status":"completed","usage":{"prompt_tokens":5,"completion_tokens":2,"total_tokens":7}}`)
+		case "/threads/thread_1/runs/run_1/steps":
+			_, _ = io.WriteString(w, `{"data":[{"id":"step_1","type":"tool_calls","status":"completed","step_details":{"type":"tool_calls",
+				"tool_calls":[{"id":"call_1","type":"function","function":{"name":"calculator","arguments":"{\"__arg1\":\"2+2\"}","output":"4"}}]}}]}`)
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	})
+
+	c, stdout, _ := newTestCLI("")
+	require.NoError(t, c.run(context.Background(), []string{"runs", "tail", "-interval", "1ms", "thread_1", "run_1"}))
+
+	out := stdout.String()
+	assert.Contains(t, out, `calculator({"__arg1":"2+2"}) -> 4`)
+	assert.Contains(t, out, "run in_progress\n")
+	assert.Contains(t, out, "run completed\n")
+	assert.Contains(t, out, "tokens: 5 prompt, 2 completion, 7 total")
+	// the step is printed once
+	assert.Equal(t, 1, strings.Count(out, "tool calls"))
+}
+
+func TestUsage(t *testing.T) {
+	c, _, stderr := newTestCLI("")
+	require.ErrorIs(t, c.run(context.Background(), []string{"assistants", "get"}), errUsage)
+	assert.Contains(t, stderr.String(), "usage: assistant")
+
+	c, _, _ = newTestCLI("")
+	require.ErrorContains(t, c.run(context.Background(), []string{"chat", "-tools", "search", "asst_1"}), `unknown tool "search"`)
+}
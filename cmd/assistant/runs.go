@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/devalexandre/mylangchaingo/agents/assistant/runner"
+)
+
+func (c *cli) runs(ctx context.Context, args []string) error {
+	if len(args) == 0 || args[0] != "tail" {
+		return c.usage()
+	}
+	return c.tailRun(ctx, args[1:])
+}
+
+// tailRun follows a run until it ends, printing its status changes and the
+// tool calls of its steps.
+func (c *cli) tailRun(ctx context.Context, args []string) error {
+	fs := c.flags("runs tail")
+	interval := fs.Duration("interval", time.Second, "how often the run is polled")
+	if err := c.parse(fs, args, 2); err != nil {
+		return err
+	}
+	threadID, runID := fs.Arg(0), fs.Arg(1)
+
+	lastStatus := ""
+	// the status of the steps already printed, by id
+	printed := map[string]string{}
+	for {
+		run, err := runner.RetrieveRun(threadID, runID)
+		if err != nil {
+			return err
+		}
+
+		steps, err := runner.ListAllRunSteps(threadID, runID)
+		if err != nil {
+			return err
+		}
+		for _, step := range steps {
+			if printed[step.ID] == step.Status {
+				continue
+			}
+			printed[step.ID] = step.Status
+			c.printStep(step)
+		}
+
+		status := ""
+		if run.Status != nil {
+			status = *run.Status
+		}
+		if status != lastStatus {
+			fmt.Fprintf(c.stdout, "%s run %s\n", time.Now().Format(time.TimeOnly), status)
+			lastStatus = status
+		}
+		if run.LastError != nil {
+			fmt.Fprintf(c.stdout, "  error %s: %s\n", run.LastError.Code, run.LastError.Message)
+		}
+
+		switch status {
+		case runner.StatusCompleted, runner.StatusFailed, runner.StatusCancelled,
+			runner.StatusExpired, runner.StatusIncomplete:
+			if run.Usage != nil {
+				fmt.Fprintf(c.stdout, "  tokens: %d prompt, %d completion, %d total\n",
+					run.Usage.PromptTokens, run.Usage.CompletionTokens, run.Usage.TotalTokens)
+			}
+			return nil
+		}
+
+		select {
+		case <-time.After(*interval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (c *cli) printStep(step runner.RunStep) {
+	now := time.Now().Format(time.TimeOnly)
+	if step.StepDetails.MessageCreation != nil {
+		fmt.Fprintf(c.stdout, "%s step %s: message %s\n", now, step.Status, step.StepDetails.MessageCreation.MessageID)
+		return
+	}
+
+	fmt.Fprintf(c.stdout, "%s step %s: tool calls\n", now, step.Status)
+	for _, call := range step.StepDetails.ToolCalls {
+		switch {
+		case call.Function != nil:
+			fmt.Fprintf(c.stdout, "  %s(%s)", call.Function.Name, call.Function.Arguments)
+			if call.Function.Output != nil {
+				fmt.Fprintf(c.stdout, " -> %s", *call.Function.Output)
+			}
+			fmt.Fprintln(c.stdout)
+		case call.CodeInterpreter != nil:
+			fmt.Fprintf(c.stdout, "  code_interpreter:\n%s\n", call.CodeInterpreter.Input)
+			for _, output := range call.CodeInterpreter.Outputs {
+				if output.Type == runner.CodeInterpreterOutputLogs {
+					fmt.Fprintf(c.stdout, "  -> %s\n", output.Logs)
+				} else if output.Image != nil {
+					fmt.Fprintf(c.stdout, "  -> image %s\n", output.Image.FileID)
+				}
+			}
+		case call.FileSearch != nil:
+			fmt.Fprintf(c.stdout, "  file_search: %d results\n", len(call.FileSearch.Results))
+		default:
+			fmt.Fprintf(c.stdout, "  %s\n", call.Type)
+		}
+	}
+}
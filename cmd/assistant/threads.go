@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/devalexandre/mylangchaingo/agents/assistant"
+	"github.com/devalexandre/mylangchaingo/agents/assistant/message"
+	"github.com/devalexandre/mylangchaingo/agents/assistant/thread"
+)
+
+func (c *cli) threads(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return c.usage()
+	}
+
+	switch args[0] {
+	case "messages":
+		return c.dumpMessages(ctx, args[1:])
+	case "export":
+		return c.exportThread(ctx, args[1:])
+	}
+	return c.usage()
+}
+
+// dumpMessages prints the messages of a thread, oldest first.
+func (c *cli) dumpMessages(ctx context.Context, args []string) error {
+	fs := c.flags("threads messages")
+	asJSON := fs.Bool("json", false, "print the messages as JSON")
+	if err := c.parse(fs, args, 1); err != nil {
+		return err
+	}
+
+	messages, err := message.IterateMessages(fs.Arg(0), assistant.WithOrder("asc"), assistant.WithLimit(100)).Collect(ctx)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		return c.printJSON(messages)
+	}
+
+	for _, msg := range messages {
+		text, err := message.RenderWithCitations(msg.Content, func(fileID string) (string, error) { return fileID, nil })
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(c.stdout, "[%s] %s:\n%s\n\n", time.Unix(int64(msg.CreatedAt), 0).Format(time.DateTime), msg.Role, text)
+	}
+	return nil
+}
+
+// exportThread writes the whole history of a thread as a Markdown transcript
+// or JSONL, optionally only the messages with some metadata.
+func (c *cli) exportThread(ctx context.Context, args []string) error {
+	fs := c.flags("threads export")
+	format := fs.String("format", "markdown", "markdown or jsonl")
+	var metadata metadataFlag
+	fs.Var(&metadata, "metadata", "only the messages with this key=value metadata, can be repeated")
+	if err := c.parse(fs, args, 1); err != nil {
+		return err
+	}
+
+	transcript, err := thread.ExportThread(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if len(metadata) > 0 {
+		transcript = transcript.Search(metadata)
+	}
+
+	switch *format {
+	case "markdown":
+		return transcript.WriteMarkdown(c.stdout, nil)
+	case "jsonl":
+		return transcript.WriteJSONL(c.stdout)
+	}
+	return fmt.Errorf("unknown format %q, use markdown or jsonl", *format)
+}
+
+// metadataFlag collects key=value flags.
+type metadataFlag map[string]string
+
+func (m *metadataFlag) String() string {
+	return fmt.Sprint(map[string]string(*m))
+}
+
+func (m *metadataFlag) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok {
+		return fmt.Errorf("expected key=value, got %q", value)
+	}
+	if *m == nil {
+		*m = metadataFlag{}
+	}
+	(*m)[key] = val
+	return nil
+}
@@ -0,0 +1,170 @@
+// Package cache wraps an embeddings.Embedder with a cache, so texts already
+// embedded by the same provider and model are not sent to the API again.
+//
+// The cache works with any embedder, like the jina embedder or an openai LLM
+// wrapped by embeddings.NewEmbedder:
+//
+//	embedder := cache.New("jina", j.Model, j, cache.NewMemoryStore(10000))
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/devalexandre/mylangchaingo/embeddings/intent"
+	"github.com/tmc/langchaingo/embeddings"
+)
+
+const (
+	kindDocument = "document"
+	kindQuery    = "query"
+)
+
+// Store keeps the cached vectors by key.
+type Store interface {
+	// Get returns the vector of key, and false when it is not cached.
+	Get(ctx context.Context, key string) ([]float32, bool, error)
+	// Put caches the vector of key.
+	Put(ctx context.Context, key string, vector []float32) error
+}
+
+// Embedder is an embeddings.Embedder that only embeds the texts missing
+// from its store.
+type Embedder struct {
+	provider  string
+	model     string
+	embedder  embeddings.Embedder
+	store     Store
+	normalize func(string) string
+}
+
+var _ embeddings.Embedder = &Embedder{}
+
+// Option configures an Embedder.
+type Option func(*Embedder)
+
+// WithNormalizer sets how texts are normalized before hashing them.
+// Defaults to NormalizeText.
+func WithNormalizer(normalize func(string) string) Option {
+	return func(e *Embedder) {
+		e.normalize = normalize
+	}
+}
+
+// New caches the vectors of embedder in store. The provider and model are
+// part of the keys, so stores can be shared by several embedders. Embedders
+// whose vectors depend on the other texts of a call, such as jina with late
+// chunking, must not be cached.
+func New(provider, model string, embedder embeddings.Embedder, store Store, opts ...Option) *Embedder {
+	e := &Embedder{
+		provider:  provider,
+		model:     model,
+		embedder:  embedder,
+		store:     store,
+		normalize: NormalizeText,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// NormalizeText trims the text and collapses its whitespace.
+func NormalizeText(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// EmbedDocuments returns a vector for each text, in order, embedding only
+// the texts that are not cached. Repeated texts are embedded once.
+func (e *Embedder) EmbedDocuments(ctx context.Context, texts []string) ([][]float32, error) {
+	return e.embed(intent.NewContext(ctx, intent.Document), kindDocument, texts, e.embedder.EmbedDocuments)
+}
+
+// EmbedQuery returns the vector of a query. Queries are cached apart from
+// documents, as some providers embed them differently, see the intent package.
+func (e *Embedder) EmbedQuery(ctx context.Context, text string) ([]float32, error) {
+	vectors, err := e.embed(intent.NewContext(ctx, intent.Query), kindQuery, []string{text}, func(ctx context.Context, texts []string) ([][]float32, error) {
+		vector, err := e.embedder.EmbedQuery(ctx, texts[0])
+		if err != nil {
+			return nil, err
+		}
+		return [][]float32{vector}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return vectors[0], nil
+}
+
+func (e *Embedder) embed(
+	ctx context.Context,
+	kind string,
+	texts []string,
+	embed func(context.Context, []string) ([][]float32, error),
+) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+
+	// positions of each missing key, and the keys and texts to embed in order
+	positions := map[string][]int{}
+	var missingKeys, missingTexts []string
+	for i, text := range texts {
+		key := e.key(kind, text)
+		if _, ok := positions[key]; ok {
+			positions[key] = append(positions[key], i)
+			continue
+		}
+
+		vector, ok, err := e.store.Get(ctx, key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the embedding cache: %w", err)
+		}
+		if ok {
+			vectors[i] = vector
+			continue
+		}
+		positions[key] = []int{i}
+		missingKeys = append(missingKeys, key)
+		missingTexts = append(missingTexts, text)
+	}
+
+	if len(missingTexts) == 0 {
+		return vectors, nil
+	}
+
+	embedded, err := embed(ctx, missingTexts)
+	if err != nil {
+		return nil, err
+	}
+	if len(embedded) != len(missingTexts) {
+		return nil, fmt.Errorf("embedder returned %d vectors for %d texts", len(embedded), len(missingTexts))
+	}
+
+	for j, key := range missingKeys {
+		if err := e.store.Put(ctx, key, embedded[j]); err != nil {
+			return nil, fmt.Errorf("failed to write the embedding cache: %w", err)
+		}
+		// repeated texts get their own copy, so changing one vector does not
+		// change the others
+		for n, i := range positions[key] {
+			if n == 0 {
+				vectors[i] = embedded[j]
+				continue
+			}
+			vectors[i] = append([]float32(nil), embedded[j]...)
+		}
+	}
+	return vectors, nil
+}
+
+// key hashes the provider, model, kind and normalized text.
+func (e *Embedder) key(kind, text string) string {
+	h := sha256.New()
+	for _, part := range []string{e.provider, e.model, kind, e.normalize(text)} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
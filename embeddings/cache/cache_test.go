@@ -0,0 +1,123 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/devalexandre/mylangchaingo/internal/sqltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingEmbedder embeds a text as its length and records the texts it got.
+type countingEmbedder struct {
+	documents []string
+	queries   []string
+}
+
+func (e *countingEmbedder) EmbedDocuments(_ context.Context, texts []string) ([][]float32, error) {
+	e.documents = append(e.documents, texts...)
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = []float32{float32(len(text)), 0.5}
+	}
+	return vectors, nil
+}
+
+func (e *countingEmbedder) EmbedQuery(_ context.Context, text string) ([]float32, error) {
+	e.queries = append(e.queries, text)
+	return []float32{float32(len(text)), -1}, nil
+}
+
+func TestEmbedder(t *testing.T) {
+	ctx := context.Background()
+	fake := &countingEmbedder{}
+	embedder := New("fake", "model-1", fake, NewMemoryStore(0))
+
+	vectors, err := embedder.EmbedDocuments(ctx, []string{"a", "bb", "a"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{1, 0.5}, {2, 0.5}, {1, 0.5}}, vectors)
+	assert.Equal(t, []string{"a", "bb"}, fake.documents)
+
+	// repeated texts do not share their vector
+	vectors[0][0] = 9
+	assert.Equal(t, []float32{1, 0.5}, vectors[2])
+
+	// only the misses are embedded, and the order is kept
+	vectors, err = embedder.EmbedDocuments(ctx, []string{"ccc", " bb\n", "a"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{3, 0.5}, {2, 0.5}, {1, 0.5}}, vectors)
+	assert.Equal(t, []string{"a", "bb", "ccc"}, fake.documents)
+
+	// queries are cached apart from documents
+	vector, err := embedder.EmbedQuery(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, []float32{1, -1}, vector)
+	_, err = embedder.EmbedQuery(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, fake.queries)
+
+	// other models do not share the vectors
+	other := New("fake", "model-2", fake, embedder.store)
+	_, err = other.EmbedDocuments(ctx, []string{"a"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "bb", "ccc", "a"}, fake.documents)
+}
+
+func TestMemoryStoreEvicts(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore(2)
+	require.NoError(t, store.Put(ctx, "a", []float32{1}))
+	require.NoError(t, store.Put(ctx, "b", []float32{2}))
+	_, _, _ = store.Get(ctx, "a")
+	require.NoError(t, store.Put(ctx, "c", []float32{3}))
+
+	assert.Equal(t, 2, store.Len())
+	_, ok, _ := store.Get(ctx, "b")
+	assert.False(t, ok)
+	_, ok, _ = store.Get(ctx, "a")
+	assert.True(t, ok)
+}
+
+func TestMemoryStoreCopiesVectors(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore(0)
+	vector := []float32{1, 2}
+	require.NoError(t, store.Put(ctx, "a", vector))
+	vector[0] = 9
+
+	got, _, err := store.Get(ctx, "a")
+	require.NoError(t, err)
+	got[1] = 9
+
+	got, _, err = store.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, []float32{1, 2}, got)
+}
+
+func TestStores(t *testing.T) {
+	ctx := context.Background()
+
+	fileStore, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+
+	db := sqltest.Open()
+	defer db.Close()
+	sqlStore, err := NewSQLStore(ctx, db)
+	require.NoError(t, err)
+
+	for name, store := range map[string]Store{"file": fileStore, "sql": sqlStore} {
+		t.Run(name, func(t *testing.T) {
+			_, ok, err := store.Get(ctx, "0123abcd")
+			require.NoError(t, err)
+			assert.False(t, ok)
+
+			require.NoError(t, store.Put(ctx, "0123abcd", []float32{0.25, -3, 1e-7}))
+			require.NoError(t, store.Put(ctx, "0123abcd", []float32{0.5, -3, 1e-7}))
+			vector, ok, err := store.Get(ctx, "0123abcd")
+			require.NoError(t, err)
+			assert.True(t, ok)
+			assert.Equal(t, []float32{0.5, -3, 1e-7}, vector)
+		})
+	}
+}
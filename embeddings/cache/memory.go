@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+)
+
+// MemoryStore keeps the vectors in memory, evicting the least recently used
+// ones past its capacity.
+type MemoryStore struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	entries  map[string]*list.Element
+}
+
+type memoryEntry struct {
+	key    string
+	vector []float32
+}
+
+// NewMemoryStore returns a store holding up to capacity vectors, or any
+// number of them when capacity is not positive.
+func NewMemoryStore(capacity int) *MemoryStore {
+	return &MemoryStore{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  map[string]*list.Element{},
+	}
+}
+
+func (s *MemoryStore) Get(_ context.Context, key string) ([]float32, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	element, ok := s.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	s.order.MoveToFront(element)
+	// callers may change the vector they get
+	return append([]float32(nil), element.Value.(*memoryEntry).vector...), true, nil
+}
+
+func (s *MemoryStore) Put(_ context.Context, key string, vector []float32) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	vector = append([]float32(nil), vector...)
+
+	if element, ok := s.entries[key]; ok {
+		element.Value.(*memoryEntry).vector = vector
+		s.order.MoveToFront(element)
+		return nil
+	}
+
+	s.entries[key] = s.order.PushFront(&memoryEntry{key: key, vector: vector})
+	if s.capacity > 0 && s.order.Len() > s.capacity {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.entries, oldest.Value.(*memoryEntry).key)
+	}
+	return nil
+}
+
+// Len returns the number of cached vectors.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.order.Len()
+}
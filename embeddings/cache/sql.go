@@ -0,0 +1,76 @@
+package cache
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/devalexandre/mylangchaingo/internal/sqltable"
+)
+
+const _defaultTable = "embedding_cache"
+
+// SQLStore keeps the vectors in a database/sql table, as base64 text.
+type SQLStore struct {
+	db    *sql.DB
+	table sqltable.Table
+}
+
+// SQLStoreOption configures a SQLStore.
+type SQLStoreOption func(*SQLStore)
+
+// WithTable sets the table of the vectors. Defaults to embedding_cache.
+func WithTable(table string) SQLStoreOption {
+	return func(s *SQLStore) {
+		s.table.Name = table
+	}
+}
+
+// WithDollarPlaceholders makes the store write $n placeholders, for PostgreSQL.
+// See internal/sqltable for the databases the statements support.
+func WithDollarPlaceholders() SQLStoreOption {
+	return func(s *SQLStore) {
+		s.table.Dollar = true
+	}
+}
+
+// NewSQLStore returns a store backed by db, creating its table if needed.
+func NewSQLStore(ctx context.Context, db *sql.DB, opts ...SQLStoreOption) (*SQLStore, error) {
+	s := &SQLStore{db: db, table: sqltable.Table{Name: _defaultTable}}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if err := s.table.Create(ctx, db, "cache_key VARCHAR(64) PRIMARY KEY", "vector TEXT NOT NULL"); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *SQLStore) Get(ctx context.Context, key string) ([]float32, bool, error) {
+	var encoded string
+	err := s.db.QueryRowContext(ctx, s.table.Query("SELECT vector FROM %s WHERE cache_key = ?"), key).Scan(&encoded)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, false, fmt.Errorf("cached vector %s: %w", key, err)
+	}
+	vector, err := decodeVector(data)
+	if err != nil {
+		return nil, false, fmt.Errorf("cached vector %s: %w", key, err)
+	}
+	return vector, true, nil
+}
+
+func (s *SQLStore) Put(ctx context.Context, key string, vector []float32) error {
+	return s.table.Replace(ctx, s.db, []string{"cache_key", "vector"},
+		key, base64.StdEncoding.EncodeToString(encodeVector(vector)))
+}
@@ -0,0 +1,68 @@
+// Package intent tells embedding providers whether texts are queries or
+// documents, as some of them embed each differently for better retrieval:
+// the jina task types and the NVIDIA input types.
+//
+// The intent travels in the context, so it reaches the CreateEmbedding of
+// the providers through any embedder:
+//
+//	embedder, err := intent.NewEmbedder(llm)
+package intent
+
+import (
+	"context"
+
+	"github.com/tmc/langchaingo/embeddings"
+)
+
+// Intent is what the embedded texts are used for.
+type Intent string
+
+const (
+	// Query texts are searched for.
+	Query Intent = "query"
+	// Document texts are searched in, also called passages.
+	Document Intent = "document"
+)
+
+type contextKey struct{}
+
+// NewContext returns a context carrying the intent.
+func NewContext(ctx context.Context, intent Intent) context.Context {
+	return context.WithValue(ctx, contextKey{}, intent)
+}
+
+// FromContext returns the intent of the context, empty when unknown.
+func FromContext(ctx context.Context) Intent {
+	intent, _ := ctx.Value(contextKey{}).(Intent)
+	return intent
+}
+
+// Embedder sets the intent of the calls to an embedder: Document for
+// EmbedDocuments and Query for EmbedQuery.
+type Embedder struct {
+	embedder embeddings.Embedder
+}
+
+var _ embeddings.Embedder = &Embedder{}
+
+// Wrap sets the intent of the calls to embedder.
+func Wrap(embedder embeddings.Embedder) *Embedder {
+	return &Embedder{embedder: embedder}
+}
+
+// NewEmbedder is embeddings.NewEmbedder, setting the intent of the calls to client.
+func NewEmbedder(client embeddings.EmbedderClient, opts ...embeddings.Option) (*Embedder, error) {
+	embedder, err := embeddings.NewEmbedder(client, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return Wrap(embedder), nil
+}
+
+func (e *Embedder) EmbedDocuments(ctx context.Context, texts []string) ([][]float32, error) {
+	return e.embedder.EmbedDocuments(NewContext(ctx, Document), texts)
+}
+
+func (e *Embedder) EmbedQuery(ctx context.Context, text string) ([]float32, error) {
+	return e.embedder.EmbedQuery(NewContext(ctx, Query), text)
+}
@@ -0,0 +1,30 @@
+package intent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/embeddings"
+)
+
+func TestEmbedderSetsIntent(t *testing.T) {
+	var intents []Intent
+	client := embeddings.EmbedderClientFunc(func(ctx context.Context, texts []string) ([][]float32, error) {
+		intents = append(intents, FromContext(ctx))
+		return make([][]float32, len(texts)), nil
+	})
+
+	embedder, err := NewEmbedder(client)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	_, err = embedder.EmbedDocuments(ctx, []string{"a", "b"})
+	require.NoError(t, err)
+	_, err = embedder.EmbedQuery(ctx, "c")
+	require.NoError(t, err)
+
+	assert.Equal(t, []Intent{Document, Query}, intents)
+	assert.Equal(t, Intent(""), FromContext(ctx))
+}
@@ -0,0 +1,157 @@
+package jina
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// BatchError is the failure of one request of EmbedDocuments.
+type BatchError struct {
+	// Indexes are the positions of the batch inputs in the texts.
+	Indexes []int
+	Err     error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("inputs %v: %v", e.Indexes, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// PartialError is returned by EmbedDocuments when some batches failed.
+// Vectors has the embeddings of the other inputs, and nil for the failed ones.
+type PartialError struct {
+	Vectors [][]float32
+	Failed  []*BatchError
+}
+
+func (e *PartialError) Error() string {
+	msgs := make([]string, 0, len(e.Failed))
+	for _, failed := range e.Failed {
+		msgs = append(msgs, failed.Error())
+	}
+	return fmt.Sprintf("%d of %d inputs failed to embed: %s",
+		len(e.FailedIndexes()), len(e.Vectors), strings.Join(msgs, "; "))
+}
+
+func (e *PartialError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Failed))
+	for _, failed := range e.Failed {
+		errs = append(errs, failed)
+	}
+	return errs
+}
+
+// FailedIndexes returns the positions of the inputs that were not embedded.
+func (e *PartialError) FailedIndexes() []int {
+	var indexes []int
+	for _, failed := range e.Failed {
+		indexes = append(indexes, failed.Indexes...)
+	}
+	return indexes
+}
+
+// EstimateTokens is a rough and conservative count of the tokens of a text,
+// used when no token counter is set.
+func EstimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + 2) / 3
+}
+
+// batch is a group of texts sent in one request.
+type batch struct {
+	indexes []int
+	texts   []string
+}
+
+// batchTexts groups the texts in batches of at most maxItems texts and
+// maxTokens tokens, keeping their order. A text over maxTokens is sent
+// alone, for the API to report it. Limits that are not positive are ignored.
+func batchTexts(texts []string, maxItems, maxTokens int, countTokens func(string) int) []batch {
+	var batches []batch
+	var current batch
+	tokens := 0
+	for i, text := range texts {
+		n := 0
+		if maxTokens > 0 {
+			n = countTokens(text)
+		}
+
+		full := maxItems > 0 && len(current.texts) >= maxItems
+		over := maxTokens > 0 && tokens+n > maxTokens
+		if len(current.texts) > 0 && (full || over) {
+			batches = append(batches, current)
+			current, tokens = batch{}, 0
+		}
+
+		current.indexes = append(current.indexes, i)
+		current.texts = append(current.texts, text)
+		tokens += n
+	}
+	if len(current.texts) > 0 {
+		batches = append(batches, current)
+	}
+	return batches
+}
+
+// embedBatches sends up to MaxConcurrency batches at a time and puts the
+// vectors back in the order of the texts.
+func (j *Jina) embedBatches(ctx context.Context, texts []string) ([][]float32, error) {
+	countTokens := j.CountTokens
+	if countTokens == nil {
+		countTokens = EstimateTokens
+	}
+	batches := batchTexts(texts, j.BatchSize, j.MaxTokensPerRequest, countTokens)
+	if j.LateChunking && len(batches) > 1 {
+		// the chunks of other batches would be embedded without each other
+		return nil, fmt.Errorf("late chunking needs the %d texts in one request, not %d batches", len(texts), len(batches))
+	}
+
+	vectors := make([][]float32, len(texts))
+	failed := make([]*BatchError, len(batches))
+	sem := make(chan struct{}, max(j.MaxConcurrency, 1))
+	var wg sync.WaitGroup
+	for i, b := range batches {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				failed[i] = &BatchError{Indexes: b.indexes, Err: ctx.Err()}
+				return
+			}
+
+			// the batches run at once, so each is traced as its own run
+			// rather than sharing the run ID of the package
+			embs, err := j.createEmbedding(ctx, b.texts, uuid.New().String())
+			if err != nil {
+				failed[i] = &BatchError{Indexes: b.indexes, Err: err}
+				return
+			}
+			for k, index := range b.indexes {
+				vectors[index] = embs[k]
+			}
+		}()
+	}
+	wg.Wait()
+
+	partial := &PartialError{Vectors: vectors}
+	for _, err := range failed {
+		if err != nil {
+			partial.Failed = append(partial.Failed, err)
+		}
+	}
+	if len(partial.Failed) > 0 {
+		return nil, partial
+	}
+	return vectors, nil
+}
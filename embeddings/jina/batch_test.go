@@ -0,0 +1,163 @@
+package jina
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchTexts(t *testing.T) {
+	words := func(text string) int { return len(strings.Fields(text)) }
+	texts := []string{"a b", "c", "d e f g h", "i", "j", "k"}
+
+	var got [][]int
+	for _, b := range batchTexts(texts, 3, 4, words) {
+		got = append(got, b.indexes)
+	}
+	// the third text is over the budget and goes alone
+	assert.Equal(t, [][]int{{0, 1}, {2}, {3, 4, 5}}, got)
+}
+
+func TestEmbedDocumentsConcurrentBatches(t *testing.T) {
+	var inFlight, maxInFlight, requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+
+		var req EmbeddingRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if req.Input[0] == "fail" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		// answer in reverse order, the vectors are placed by index
+		var resp EmbeddingResponse
+		for i := len(req.Input) - 1; i >= 0; i-- {
+			resp.Data = append(resp.Data, EmbeddingData{
+				Index:     i,
+				Embedding: []float32{float32(len(req.Input[i]))},
+			})
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+	defer srv.Close()
+
+	t.Setenv("LANGCHAIN_TRACING", "false")
+	j, err := NewJina(WithAPIBaseURL(srv.URL), WithAPIKey("key"), WithMaxConcurrency(2), WithBatchSize(2))
+	require.NoError(t, err)
+
+	texts := make([]string, 10)
+	for i := range texts {
+		texts[i] = strings.Repeat("x", i+1)
+	}
+	vectors, err := j.EmbedDocuments(context.Background(), texts)
+	require.NoError(t, err)
+	for i, vector := range vectors {
+		assert.Equal(t, []float32{float32(i + 1)}, vector, fmt.Sprint("input ", i))
+	}
+	assert.EqualValues(t, 5, requests.Load())
+	assert.LessOrEqual(t, maxInFlight.Load(), int32(2))
+
+	// the failed inputs are reported with the vectors of the others
+	_, err = j.EmbedDocuments(context.Background(), []string{"a", "b", "fail", "c"})
+	var partial *PartialError
+	require.True(t, errors.As(err, &partial))
+	assert.Equal(t, []int{2, 3}, partial.FailedIndexes())
+	assert.Equal(t, []float32{1}, partial.Vectors[0])
+	assert.Nil(t, partial.Vectors[2])
+}
+
+func TestEmbedDocumentsTracesEachBatch(t *testing.T) {
+	var mu sync.Mutex
+	posted := map[string]int{}
+	var patched []string
+	langsmith := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Method == http.MethodPatch {
+			patched = append(patched, strings.TrimPrefix(r.URL.Path, "/runs/"))
+			return
+		}
+		var run struct {
+			ID string `json:"id"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&run))
+		posted[run.ID]++
+	}))
+	defer langsmith.Close()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req EmbeddingRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		var resp EmbeddingResponse
+		for i := range req.Input {
+			resp.Data = append(resp.Data, EmbeddingData{Index: i, Embedding: []float32{1}})
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+	defer srv.Close()
+
+	t.Setenv("LANGCHAIN_TRACING", "true")
+	t.Setenv("LANGSMITH_API_KEY", "key")
+	t.Setenv("LANGSMITH_URL", langsmith.URL)
+	t.Setenv("LANGSMITH_PROJECT_NAME", "test")
+	j, err := NewJina(WithAPIBaseURL(srv.URL), WithAPIKey("key"), WithMaxConcurrency(4), WithBatchSize(1))
+	require.NoError(t, err)
+
+	_, err = j.EmbedDocuments(context.Background(), []string{"a", "b", "c", "d"})
+	require.NoError(t, err)
+
+	// each batch starts and ends its own run
+	assert.Len(t, posted, 4)
+	var ids []string
+	for id, n := range posted {
+		assert.Equal(t, 1, n, id)
+		ids = append(ids, id)
+	}
+	assert.ElementsMatch(t, ids, patched)
+}
+
+func TestEmbedDocumentsLateChunkingInOneRequest(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		var req EmbeddingRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		var resp EmbeddingResponse
+		for i := range req.Input {
+			resp.Data = append(resp.Data, EmbeddingData{Index: i, Embedding: []float32{1}})
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+	defer srv.Close()
+
+	t.Setenv("LANGCHAIN_TRACING", "false")
+	j, err := NewJina(WithAPIBaseURL(srv.URL), WithAPIKey("key"), WithModel(V3Model), WithLateChunking(true), WithBatchSize(2))
+	require.NoError(t, err)
+
+	vectors, err := j.EmbedDocuments(context.Background(), []string{"a", "b"})
+	require.NoError(t, err)
+	assert.Len(t, vectors, 2)
+
+	_, err = j.EmbedDocuments(context.Background(), []string{"a", "b", "c"})
+	require.ErrorContains(t, err, "late chunking needs the 3 texts in one request, not 2 batches")
+	assert.EqualValues(t, 1, requests.Load())
+}
@@ -0,0 +1,55 @@
+package jina
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// decodeEmbedding decodes a vector of the embeddingType encoding.
+func decodeEmbedding(raw json.RawMessage, embeddingType string) ([]float32, error) {
+	switch embeddingType {
+	case "", EmbeddingTypeFloat:
+		var emb []float32
+		err := json.Unmarshal(raw, &emb)
+		return emb, err
+	case EmbeddingTypeBase64:
+		var encoded string
+		if err := json.Unmarshal(raw, &encoded); err != nil {
+			return nil, err
+		}
+		data, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, err
+		}
+		if len(data)%4 != 0 {
+			return nil, fmt.Errorf("invalid base64 embedding of %d bytes", len(data))
+		}
+		emb := make([]float32, len(data)/4)
+		for i := range emb {
+			emb[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[4*i:]))
+		}
+		return emb, nil
+	case EmbeddingTypeBinary, EmbeddingTypeUBinary:
+		// the bits are packed in int8 or uint8 values, the same bytes either way
+		var packed []int
+		if err := json.Unmarshal(raw, &packed); err != nil {
+			return nil, err
+		}
+		emb := make([]float32, 0, 8*len(packed))
+		for _, v := range packed {
+			b := byte(v)
+			for bit := 7; bit >= 0; bit-- {
+				if b&(1<<bit) != 0 {
+					emb = append(emb, 1)
+				} else {
+					emb = append(emb, -1)
+				}
+			}
+		}
+		return emb, nil
+	}
+	return nil, fmt.Errorf("unknown embedding type %q", embeddingType)
+}
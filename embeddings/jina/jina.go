@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"github.com/devalexandre/langsmithgo"
 	"github.com/devalexandre/mylangchaingo"
+	"github.com/devalexandre/mylangchaingo/embeddings/intent"
 	"github.com/google/uuid"
 	"io"
 	"net/http"
@@ -19,19 +20,45 @@ import (
 )
 
 type Jina struct {
-	Model               string
-	InputText           []string
-	StripNewLines       bool
-	BatchSize           int
-	APIBaseURL          string
-	APIKey              string
+	Model         string
+	InputText     []string
+	StripNewLines bool
+	BatchSize     int
+	APIBaseURL    string
+	APIKey        string
+	// MultiVectorBaseURL is the endpoint of CreateMultiVectorEmbedding.
+	MultiVectorBaseURL string
+	// MaxConcurrency is the number of batches sent at the same time.
+	MaxConcurrency int
+	// MaxTokensPerRequest is the token budget of a batch.
+	MaxTokensPerRequest int
+	// CountTokens counts the tokens of a text. Defaults to EstimateTokens.
+	CountTokens func(string) int
+	// Task adapts the embeddings of the v3 and clip-v2 models, see TaskRetrievalQuery.
+	Task string
+	// Dimensions truncates the vectors of the Matryoshka models.
+	Dimensions int
+	// LateChunking embeds the inputs of a request as chunks of a single text.
+	// EmbedDocuments then rejects texts that do not fit in one request. Do not
+	// wrap it in a cache.Embedder, which only sends the texts missing from the
+	// cache and so changes the text they are chunks of.
+	LateChunking bool
+	// EmbeddingType is the encoding of the vectors in the responses, see EmbeddingTypeFloat.
+	EmbeddingType string
+	// Normalized asks for vectors of length 1 when set.
+	Normalized          *bool
 	langsmithClient     *langsmithgo.Client
 	langsmithgoParentId string
 }
 
 type EmbeddingRequest struct {
-	Input []string `json:"input"`
-	Model string   `json:"model"`
+	Input         []string `json:"input"`
+	Model         string   `json:"model"`
+	Task          string   `json:"task,omitempty"`
+	Dimensions    int      `json:"dimensions,omitempty"`
+	LateChunking  bool     `json:"late_chunking,omitempty"`
+	EmbeddingType string   `json:"embedding_type,omitempty"`
+	Normalized    *bool    `json:"normalized,omitempty"`
 }
 
 type EmbeddingResponse struct {
@@ -41,10 +68,22 @@ type EmbeddingResponse struct {
 		TotalTokens  int `json:"total_tokens"`
 		PromptTokens int `json:"prompt_tokens"`
 	} `json:"usage"`
+	Data []EmbeddingData `json:"data"`
+}
+
+// EmbeddingData is a vector of an EmbeddingResponse.
+type EmbeddingData struct {
+	Object    string    `json:"object"`
+	Index     int       `json:"index"`
+	Embedding []float32 `json:"embedding"`
+}
+
+// encodedEmbeddingResponse is an EmbeddingResponse whose vectors are left
+// encoded as the EmbeddingType of the request.
+type encodedEmbeddingResponse struct {
 	Data []struct {
-		Object    string    `json:"object"`
-		Index     int       `json:"index"`
-		Embedding []float32 `json:"embedding"`
+		Index     int             `json:"index"`
+		Embedding json.RawMessage `json:"embedding"`
 	} `json:"data"`
 }
 
@@ -65,23 +104,12 @@ func NewJina(opts ...Option) (*Jina, error) {
 	return v, nil
 }
 
-// EmbedDocuments returns a vector for each text.
+// EmbedDocuments returns a vector for each text, in order. The texts are
+// sent in concurrent batches limited by BatchSize and MaxTokensPerRequest;
+// when some batches fail, the error is a *PartialError.
 func (j *Jina) EmbedDocuments(ctx context.Context, texts []string) ([][]float32, error) {
-	batchedTexts := embeddings.BatchTexts(
-		embeddings.MaybeRemoveNewLines(texts, j.StripNewLines),
-		j.BatchSize,
-	)
-
-	emb := make([][]float32, 0, len(texts))
-	for _, batch := range batchedTexts {
-		curBatchEmbeddings, err := j.CreateEmbedding(ctx, batch)
-		if err != nil {
-			return nil, err
-		}
-		emb = append(emb, curBatchEmbeddings...)
-	}
-
-	return emb, nil
+	ctx = intent.NewContext(ctx, intent.Document)
+	return j.embedBatches(ctx, embeddings.MaybeRemoveNewLines(texts, j.StripNewLines))
 }
 
 // EmbedQuery returns a vector for a single text.
@@ -90,7 +118,7 @@ func (j *Jina) EmbedQuery(ctx context.Context, text string) ([]float32, error) {
 		text = strings.ReplaceAll(text, "\n", " ")
 	}
 
-	emb, err := j.CreateEmbedding(ctx, []string{text})
+	emb, err := j.CreateEmbedding(intent.NewContext(ctx, intent.Query), []string{text})
 	if err != nil {
 		return nil, err
 	}
@@ -99,35 +127,49 @@ func (j *Jina) EmbedQuery(ctx context.Context, text string) ([]float32, error) {
 }
 
 // CreateEmbedding sends texts to the Jina API and retrieves their embeddings.
+// Unless a Task is set, the models with tasks embed the texts as passages or
+// queries after the intent of the context, see the intent package.
 func (j *Jina) CreateEmbedding(ctx context.Context, texts []string) ([][]float32, error) {
-
-	requestBody := EmbeddingRequest{
-		Input: texts,
-		Model: j.Model,
-	}
-	jsonData, err := json.Marshal(requestBody)
+	embs, err := j.createEmbedding(ctx, texts, mylangchaingo.GetRunId())
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, j.APIBaseURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, err
+	//update valies runId and ParentId
+	mylangchaingo.SetParentId(mylangchaingo.GetRunId())
+	mylangchaingo.SetRunId(uuid.New().String())
+
+	return embs, nil
+}
+
+// createEmbedding sends texts to the Jina API, traced as the langsmith run
+// runID when tracing is on.
+func (j *Jina) createEmbedding(ctx context.Context, texts []string, runID string) ([][]float32, error) {
+	if Models[j.Model].MultiVector {
+		return nil, fmt.Errorf("model %s returns multi-vector embeddings, use CreateMultiVectorEmbedding", j.Model)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+j.APIKey)
+	requestBody := EmbeddingRequest{
+		Input:         texts,
+		Model:         j.Model,
+		Task:          j.task(ctx),
+		Dimensions:    j.Dimensions,
+		LateChunking:  j.LateChunking,
+		EmbeddingType: j.EmbeddingType,
+		Normalized:    j.Normalized,
+	}
 
 	if j.langsmithClient != nil {
 		err := j.langsmithClient.Run(&langsmithgo.RunPayload{
 			Name:        "Jina - Create Embedding",
 			SessionName: os.Getenv("LANGCHAIN_PROJECT_NAME"),
 			RunType:     langsmithgo.Embedding,
-			RunID:       mylangchaingo.GetRunId(),
+			RunID:       runID,
 			ParentID:    mylangchaingo.GetRootId(),
 			Inputs: map[string]interface{}{
-				"Input": texts,
-				"Model": j.Model,
+				"Input": requestBody.Input,
+				"Model": requestBody.Model,
+				"Task":  requestBody.Task,
 			},
 			Extras: map[string]interface{}{
 				"metadata": map[string]interface{}{
@@ -145,36 +187,38 @@ func (j *Jina) CreateEmbedding(ctx context.Context, texts []string) ([][]float32
 		}
 	}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, errors.New("API request failed with status: " + resp.Status)
-	}
-
-	var embeddingResponse EmbeddingResponse
-	body, err := io.ReadAll(resp.Body)
+	body, err := j.post(ctx, j.APIBaseURL, requestBody)
 	if err != nil {
 		return nil, err
 	}
 
+	var embeddingResponse encodedEmbeddingResponse
 	err = json.Unmarshal(body, &embeddingResponse)
 	if err != nil {
 		return nil, err
 	}
 
-	embs := make([][]float32, 0, len(embeddingResponse.Data))
+	// the data is not guaranteed to be in the order of the inputs
+	embs := make([][]float32, len(texts))
 	for _, data := range embeddingResponse.Data {
-		embs = append(embs, data.Embedding)
+		if data.Index < 0 || data.Index >= len(texts) || embs[data.Index] != nil {
+			return nil, fmt.Errorf("unexpected embedding index %d for %d inputs", data.Index, len(texts))
+		}
+		emb, err := decodeEmbedding(data.Embedding, j.EmbeddingType)
+		if err != nil {
+			return nil, fmt.Errorf("embedding %d: %w", data.Index, err)
+		}
+		embs[data.Index] = emb
+	}
+	for i, emb := range embs {
+		if emb == nil {
+			return nil, fmt.Errorf("missing embedding for input %d", i)
+		}
 	}
 
 	if j.langsmithClient != nil {
 		err := j.langsmithClient.Run(&langsmithgo.RunPayload{
-			RunID: mylangchaingo.GetRunId(),
+			RunID: runID,
 			Outputs: map[string]interface{}{
 				"output": embs,
 			},
@@ -185,9 +229,49 @@ func (j *Jina) CreateEmbedding(ctx context.Context, texts []string) ([][]float32
 		}
 	}
 
-	//update valies runId and ParentId
-	mylangchaingo.SetParentId(mylangchaingo.GetRunId())
-	mylangchaingo.SetRunId(uuid.New().String())
-
 	return embs, nil
 }
+
+// task returns the Task, or the retrieval task of the context intent.
+func (j *Jina) task(ctx context.Context) string {
+	if j.Task != "" || !Models[j.Model].Tasks {
+		return j.Task
+	}
+
+	switch intent.FromContext(ctx) {
+	case intent.Query:
+		return TaskRetrievalQuery
+	case intent.Document:
+		return TaskRetrievalPassage
+	}
+	return ""
+}
+
+// post sends a request to the API and returns the body of the response.
+func (j *Jina) post(ctx context.Context, url string, request any) ([]byte, error) {
+	jsonData, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+j.APIKey)
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New("API request failed with status: " + resp.Status)
+	}
+
+	return io.ReadAll(resp.Body)
+}
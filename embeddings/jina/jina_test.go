@@ -15,15 +15,20 @@ func TestMain(m *testing.M) {
 	os.Setenv("LANGCHAIN_PROJECT_NAME", "jina")
 	os.Setenv("OPENAI_API_KEY", "")
 	os.Setenv("JINA_API_KEY", "")
-	if jinakey := os.Getenv("JINA_API_KEY"); jinakey == "" {
-		os.Exit(0)
+	os.Exit(m.Run())
+}
 
+// skipWithoutAPIKey skips the tests calling the Jina API.
+func skipWithoutAPIKey(t *testing.T) {
+	t.Helper()
+	if os.Getenv("JINA_API_KEY") == "" {
+		t.Skip("JINA_API_KEY not set")
 	}
-	os.Exit(m.Run())
 }
 
 func TestJinaEmbeddings(t *testing.T) {
 	t.Parallel()
+	skipWithoutAPIKey(t)
 
 	j, err := NewJina()
 	require.NoError(t, err)
@@ -39,6 +44,7 @@ func TestJinaEmbeddings(t *testing.T) {
 // with model option
 func TestJinaEmbeddingsWithSamllModel(t *testing.T) {
 	t.Parallel()
+	skipWithoutAPIKey(t)
 
 	j, err := NewJina(WithModel(SmallModel))
 	_, err = j.EmbedQuery(context.Background(), "Hello world!")
@@ -51,6 +57,7 @@ func TestJinaEmbeddingsWithSamllModel(t *testing.T) {
 
 func TestJinaEmbeddingsWithBaseModelModel(t *testing.T) {
 	t.Parallel()
+	skipWithoutAPIKey(t)
 
 	j, err := NewJina(WithModel(BaseModel))
 	_, err = j.EmbedQuery(context.Background(), "Hello world!")
@@ -63,6 +70,7 @@ func TestJinaEmbeddingsWithBaseModelModel(t *testing.T) {
 
 func TestJinaEmbeddingsWithLargeModelModel(t *testing.T) {
 	t.Parallel()
+	skipWithoutAPIKey(t)
 
 	j, err := NewJina(WithModel(LargeModel))
 	_, err = j.EmbedQuery(context.Background(), "Hello world!")
@@ -0,0 +1,55 @@
+package jina
+
+const (
+	V3Model        = "jina-embeddings-v3"
+	ClipV1Model    = "jina-clip-v1"
+	ClipV2Model    = "jina-clip-v2"
+	ColbertV1Model = "jina-colbert-v1-en"
+	ColbertV2Model = "jina-colbert-v2"
+
+	MultiVectorURL = "https://api.jina.ai/v1/multi-vector"
+)
+
+// Tasks of the v3 and clip-v2 models, which adapt the embeddings to their use.
+const (
+	TaskRetrievalQuery   = "retrieval.query"
+	TaskRetrievalPassage = "retrieval.passage"
+	TaskClassification   = "classification"
+	TaskTextMatching     = "text-matching"
+	TaskSeparation       = "separation"
+)
+
+// Embedding types returned by the API. Binary embeddings are decoded to one
+// value per dimension, 1 or -1.
+const (
+	EmbeddingTypeFloat   = "float"
+	EmbeddingTypeBinary  = "binary"
+	EmbeddingTypeUBinary = "ubinary"
+	EmbeddingTypeBase64  = "base64"
+)
+
+// ModelInfo describes a model of the catalog.
+type ModelInfo struct {
+	// Dimensions is the default size of the vectors.
+	Dimensions int
+	// MaxTokens is the context length of an input.
+	MaxTokens int
+	// BatchSize is the number of inputs of a request.
+	BatchSize int
+	// Tasks tells if the model accepts a task.
+	Tasks bool
+	// MultiVector models return a vector per token, see CreateMultiVectorEmbedding.
+	MultiVector bool
+}
+
+// Models is the catalog of the Jina embedding models.
+var Models = map[string]ModelInfo{
+	SmallModel:     {Dimensions: 512, MaxTokens: 8192, BatchSize: 2048},
+	BaseModel:      {Dimensions: 768, MaxTokens: 8192, BatchSize: 2048},
+	LargeModel:     {Dimensions: 1024, MaxTokens: 8192, BatchSize: 2048},
+	V3Model:        {Dimensions: 1024, MaxTokens: 8192, BatchSize: 2048, Tasks: true},
+	ClipV1Model:    {Dimensions: 768, MaxTokens: 8192, BatchSize: 256},
+	ClipV2Model:    {Dimensions: 1024, MaxTokens: 8192, BatchSize: 256, Tasks: true},
+	ColbertV1Model: {Dimensions: 128, MaxTokens: 8192, BatchSize: 256, MultiVector: true},
+	ColbertV2Model: {Dimensions: 128, MaxTokens: 8192, BatchSize: 256, MultiVector: true},
+}
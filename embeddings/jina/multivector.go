@@ -0,0 +1,72 @@
+package jina
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Input types of the multi-vector models.
+const (
+	InputTypeDocument = "document"
+	InputTypeQuery    = "query"
+)
+
+type MultiVectorRequest struct {
+	Input         []string `json:"input"`
+	Model         string   `json:"model"`
+	InputType     string   `json:"input_type,omitempty"`
+	Dimensions    int      `json:"dimensions,omitempty"`
+	EmbeddingType string   `json:"embedding_type,omitempty"`
+}
+
+type MultiVectorResponse struct {
+	Model string `json:"model"`
+	Data  []struct {
+		Index      int               `json:"index"`
+		Embeddings []json.RawMessage `json:"embeddings"`
+	} `json:"data"`
+}
+
+// CreateMultiVectorEmbedding returns a vector per token of each text, as the
+// colbert models do for late interaction. inputType is InputTypeDocument or
+// InputTypeQuery.
+func (j *Jina) CreateMultiVectorEmbedding(ctx context.Context, texts []string, inputType string) ([][][]float32, error) {
+	body, err := j.post(ctx, j.MultiVectorBaseURL, MultiVectorRequest{
+		Input:         texts,
+		Model:         j.Model,
+		InputType:     inputType,
+		Dimensions:    j.Dimensions,
+		EmbeddingType: j.EmbeddingType,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var response MultiVectorResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, err
+	}
+
+	embs := make([][][]float32, len(texts))
+	for _, data := range response.Data {
+		if data.Index < 0 || data.Index >= len(texts) || embs[data.Index] != nil {
+			return nil, fmt.Errorf("unexpected embedding index %d for %d inputs", data.Index, len(texts))
+		}
+		vectors := make([][]float32, 0, len(data.Embeddings))
+		for _, raw := range data.Embeddings {
+			emb, err := decodeEmbedding(raw, j.EmbeddingType)
+			if err != nil {
+				return nil, fmt.Errorf("embedding %d: %w", data.Index, err)
+			}
+			vectors = append(vectors, emb)
+		}
+		embs[data.Index] = vectors
+	}
+	for i, emb := range embs {
+		if emb == nil {
+			return nil, fmt.Errorf("missing embedding for input %d", i)
+		}
+	}
+	return embs, nil
+}
@@ -7,7 +7,9 @@ import (
 const (
 	_defaultStripNewLines = true
 	_defaultModel         = "jina-embeddings-v2-small-en"
-	_defaultTask          = "feature-extraction"
+	_defaultConcurrency   = 4
+	_defaultMaxTokens     = 8192
+	_defaultBatchSize     = 512
 	SmallModel            = "jina-embeddings-v2-small-en"
 	BaseModel             = "jina-embeddings-v2-base-en"
 	LargeModel            = "jina-embeddings-v2-large-en"
@@ -38,6 +40,27 @@ func WithBatchSize(batchSize int) Option {
 	}
 }
 
+// WithMaxConcurrency is an option for specifying how many batches are sent at the same time.
+func WithMaxConcurrency(n int) Option {
+	return func(p *Jina) {
+		p.MaxConcurrency = n
+	}
+}
+
+// WithMaxTokensPerRequest is an option for specifying the token budget of a batch.
+func WithMaxTokensPerRequest(n int) Option {
+	return func(p *Jina) {
+		p.MaxTokensPerRequest = n
+	}
+}
+
+// WithTokenCounter is an option for specifying how the tokens of a text are counted.
+func WithTokenCounter(count func(string) int) Option {
+	return func(p *Jina) {
+		p.CountTokens = count
+	}
+}
+
 // WithAPIBaseURL is an option for specifying the API base URL.
 func WithAPIBaseURL(apiBaseURL string) Option {
 	return func(p *Jina) {
@@ -45,6 +68,49 @@ func WithAPIBaseURL(apiBaseURL string) Option {
 	}
 }
 
+// WithMultiVectorURL is an option for specifying the multi-vector API URL.
+func WithMultiVectorURL(url string) Option {
+	return func(p *Jina) {
+		p.MultiVectorBaseURL = url
+	}
+}
+
+// WithTask is an option for specifying the task of the v3 and clip-v2 models.
+func WithTask(task string) Option {
+	return func(p *Jina) {
+		p.Task = task
+	}
+}
+
+// WithDimensions is an option for truncating the vectors of the Matryoshka models.
+func WithDimensions(dimensions int) Option {
+	return func(p *Jina) {
+		p.Dimensions = dimensions
+	}
+}
+
+// WithLateChunking is an option for embedding the inputs of a request as
+// chunks of one text, see Jina.LateChunking.
+func WithLateChunking(lateChunking bool) Option {
+	return func(p *Jina) {
+		p.LateChunking = lateChunking
+	}
+}
+
+// WithEmbeddingType is an option for specifying the encoding of the vectors.
+func WithEmbeddingType(embeddingType string) Option {
+	return func(p *Jina) {
+		p.EmbeddingType = embeddingType
+	}
+}
+
+// WithNormalized is an option for asking vectors of length 1, or not.
+func WithNormalized(normalized bool) Option {
+	return func(p *Jina) {
+		p.Normalized = &normalized
+	}
+}
+
 // WithAPIKey is an option for specifying the API key.
 func WithAPIKey(apiKey string) Option {
 	return func(p *Jina) {
@@ -60,27 +126,27 @@ func WithLangsmithParentId(parentId string) Option {
 }
 
 func applyOptions(opts ...Option) *Jina {
-	_models := map[string]int{
-		"jina-embeddings-v2-small-en": 512,
-		"jina-embeddings-v2-base-en":  768,
-		"jina-embeddings-v2-large-en": 1024,
-	}
-
 	o := &Jina{
-		StripNewLines: _defaultStripNewLines,
-		BatchSize:     _models[_defaultModel],
-		Model:         _defaultModel,
-		APIBaseURL:    APIBaseURL,
-		APIKey:        os.Getenv("JINA_API_KEY"),
+		StripNewLines:       _defaultStripNewLines,
+		Model:               _defaultModel,
+		APIBaseURL:          APIBaseURL,
+		MultiVectorBaseURL:  MultiVectorURL,
+		APIKey:              os.Getenv("JINA_API_KEY"),
+		MaxConcurrency:      _defaultConcurrency,
+		MaxTokensPerRequest: _defaultMaxTokens,
+		CountTokens:         EstimateTokens,
 	}
 
 	for _, opt := range opts {
 		opt(o)
 	}
 
-	// verify if model exists in the map
-	if _, ok := _models[o.Model]; ok {
-		o.BatchSize = _models[o.Model]
+	// use the batch size of the model unless one was given
+	if o.BatchSize == 0 {
+		o.BatchSize = _defaultBatchSize
+		if model, ok := Models[o.Model]; ok {
+			o.BatchSize = model.BatchSize
+		}
 	}
 
 	return o
@@ -0,0 +1,104 @@
+package jina
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestV3Options(t *testing.T) {
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		// 0.5 and -2 as little endian float32
+		_, _ = io.WriteString(w, `{"data":[{"index":0,"embedding":"AAAAPwAAAMA="}]}`)
+	}))
+	defer srv.Close()
+
+	t.Setenv("LANGCHAIN_TRACING", "false")
+	j, err := NewJina(
+		WithAPIBaseURL(srv.URL),
+		WithModel(V3Model),
+		WithTask(TaskTextMatching),
+		WithDimensions(256),
+		WithLateChunking(true),
+		WithEmbeddingType(EmbeddingTypeBase64),
+		WithNormalized(false),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, 2048, j.BatchSize)
+
+	vector, err := j.EmbedQuery(context.Background(), "hello")
+	require.NoError(t, err)
+	assert.Equal(t, []float32{0.5, -2}, vector)
+	assert.Equal(t, map[string]any{
+		"input":          []any{"hello"},
+		"model":          V3Model,
+		"task":           TaskTextMatching,
+		"dimensions":     float64(256),
+		"late_chunking":  true,
+		"embedding_type": EmbeddingTypeBase64,
+		"normalized":     false,
+	}, got)
+}
+
+func TestDecodeBinaryEmbedding(t *testing.T) {
+	// 0b10100000 and 0b00000001, as int8 and uint8
+	for embeddingType, raw := range map[string]string{EmbeddingTypeBinary: "[-96, 1]", EmbeddingTypeUBinary: "[160, 1]"} {
+		emb, err := decodeEmbedding(json.RawMessage(raw), embeddingType)
+		require.NoError(t, err)
+		assert.Equal(t, []float32{1, -1, 1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, 1}, emb, embeddingType)
+	}
+}
+
+func TestMultiVectorEmbedding(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req MultiVectorRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, InputTypeQuery, req.InputType)
+		_, _ = io.WriteString(w, `{"data":[{"index":1,"embeddings":[[3]]},{"index":0,"embeddings":[[1],[2]]}]}`)
+	}))
+	defer srv.Close()
+
+	t.Setenv("LANGCHAIN_TRACING", "false")
+	j, err := NewJina(WithModel(ColbertV2Model), WithMultiVectorURL(srv.URL))
+	require.NoError(t, err)
+
+	embs, err := j.CreateMultiVectorEmbedding(context.Background(), []string{"a b", "c"}, InputTypeQuery)
+	require.NoError(t, err)
+	assert.Equal(t, [][][]float32{{{1}, {2}}, {{3}}}, embs)
+
+	_, err = j.EmbedQuery(context.Background(), "a")
+	assert.ErrorContains(t, err, "CreateMultiVectorEmbedding")
+}
+
+func TestRetrievalTasks(t *testing.T) {
+	var tasks []any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		tasks = append(tasks, req["task"])
+		_, _ = io.WriteString(w, `{"data":[{"index":0,"embedding":[1]}]}`)
+	}))
+	defer srv.Close()
+
+	t.Setenv("LANGCHAIN_TRACING", "false")
+	ctx := context.Background()
+	for _, model := range []string{V3Model, SmallModel} {
+		j, err := NewJina(WithAPIBaseURL(srv.URL), WithModel(model))
+		require.NoError(t, err)
+		_, err = j.EmbedDocuments(ctx, []string{"a passage"})
+		require.NoError(t, err)
+		_, err = j.EmbedQuery(ctx, "a question")
+		require.NoError(t, err)
+	}
+
+	// the v2 models have no tasks
+	assert.Equal(t, []any{TaskRetrievalPassage, TaskRetrievalQuery, nil, nil}, tasks)
+}
@@ -0,0 +1,25 @@
+// Package assistanttest points the assistant packages at a test server, so
+// their tests run without the OpenAI API.
+package assistanttest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/devalexandre/mylangchaingo/agents/assistant"
+)
+
+// UseServer serves the requests of the assistant packages with handler until
+// the test ends.
+func UseServer(t *testing.T, handler http.HandlerFunc) {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	baseURL := assistant.BaseURL
+	assistant.BaseURL = srv.URL
+	t.Cleanup(func() {
+		assistant.BaseURL = baseURL
+		srv.Close()
+	})
+}
@@ -0,0 +1,67 @@
+// Package atomicfile writes files through a temporary file in the same
+// directory, so readers never see a partial file and failed writes leave
+// nothing behind.
+package atomicfile
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Write replaces the file at path with data.
+func Write(path string, data []byte) error {
+	return writeTemp(path, bytes.NewReader(data), func(tmp string) error {
+		return os.Rename(tmp, path)
+	})
+}
+
+// WriteNew saves the content of r at path without replacing an existing
+// file: when the name is taken a number is added to it, as in chart-1.png.
+// It returns the path of the file.
+func WriteNew(path string, r io.Reader) (string, error) {
+	ext := filepath.Ext(path)
+	var saved string
+	err := writeTemp(path, r, func(tmp string) error {
+		// unlike a rename, a link fails when the name is taken
+		for i := 0; ; i++ {
+			saved = path
+			if i > 0 {
+				saved = fmt.Sprintf("%s-%d%s", strings.TrimSuffix(path, ext), i, ext)
+			}
+
+			err := os.Link(tmp, saved)
+			if !errors.Is(err, fs.ErrExist) {
+				return err
+			}
+		}
+	})
+	if err != nil {
+		return "", err
+	}
+	return saved, nil
+}
+
+// writeTemp copies r to a temporary file next to path and hands it to place,
+// removing it afterwards.
+func writeTemp(path string, r io.Reader, place func(tmp string) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return place(tmp.Name())
+}
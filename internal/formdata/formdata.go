@@ -0,0 +1,46 @@
+// Package formdata streams multipart file uploads, so the uploaded content
+// is never fully held in memory.
+package formdata
+
+import (
+	"io"
+	"mime/multipart"
+	"sort"
+)
+
+// StreamFile returns a multipart form with the fields and a "file" part
+// named filename, written while the form is read, and its content type.
+// Errors reading content are returned by the reads of the form, which the
+// caller must close.
+func StreamFile(fields map[string]string, filename string, content io.Reader) (io.ReadCloser, string) {
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	go func() {
+		var err error
+		for _, name := range names {
+			if err = writer.WriteField(name, fields[name]); err != nil {
+				break
+			}
+		}
+		if err == nil {
+			var part io.Writer
+			part, err = writer.CreateFormFile("file", filename)
+			if err == nil {
+				_, err = io.Copy(part, content)
+			}
+		}
+		if err == nil {
+			err = writer.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	return pr, writer.FormDataContentType()
+}
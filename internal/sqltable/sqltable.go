@@ -0,0 +1,80 @@
+// Package sqltable runs the statements of the SQL stores. The statements are
+// portable across SQLite, MySQL and PostgreSQL: tables are created with plain
+// column types, rows are replaced by a delete and an insert rather than an
+// upsert, and the ? placeholders are rewritten for drivers expecting $n.
+package sqltable
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// Table is the table of a store.
+type Table struct {
+	Name string
+	// Dollar uses $1, $2... placeholders, as PostgreSQL drivers expect, instead of ?.
+	Dollar bool
+}
+
+// Create creates the table with the columns if it does not exist.
+func (t Table) Create(ctx context.Context, db *sql.DB, columns ...string) error {
+	_, err := db.ExecContext(ctx, fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (\n\t%s\n)", t.Name, strings.Join(columns, ",\n\t")))
+	if err != nil {
+		return fmt.Errorf("failed to create table %s: %w", t.Name, err)
+	}
+	return nil
+}
+
+// Query sets the table name of format and rewrites its placeholders when needed.
+func (t Table) Query(format string) string {
+	query := fmt.Sprintf(format, t.Name)
+	if !t.Dollar {
+		return query
+	}
+
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			fmt.Fprintf(&b, "$%d", n)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Replace inserts the row keyed by its first column, deleting the previous
+// one in the same transaction. A concurrent writer of the same key can insert
+// its row between the delete and the insert, which then fails on the primary
+// key, so a failed insert is retried once.
+func (t Table) Replace(ctx context.Context, db *sql.DB, columns []string, values ...any) error {
+	retry, err := t.replace(ctx, db, columns, values)
+	if retry {
+		_, err = t.replace(ctx, db, columns, values)
+	}
+	return err
+}
+
+// replace runs the delete and the insert, and reports whether the insert failed.
+func (t Table) replace(ctx context.Context, db *sql.DB, columns []string, values []any) (bool, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if _, err := tx.ExecContext(ctx, t.Query("DELETE FROM %s WHERE "+columns[0]+" = ?"), values[0]); err != nil {
+		return false, err
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+	insert := fmt.Sprintf("INSERT INTO %%s (%s) VALUES (%s)", strings.Join(columns, ", "), placeholders)
+	if _, err := tx.ExecContext(ctx, t.Query(insert), values...); err != nil {
+		return ctx.Err() == nil, err
+	}
+	return false, tx.Commit()
+}
@@ -0,0 +1,40 @@
+package sqltable
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/devalexandre/mylangchaingo/internal/sqltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplaceRetriesConcurrentInsert(t *testing.T) {
+	ctx := context.Background()
+	table := Table{Name: "entries", Dollar: true}
+
+	var db *sql.DB
+	deletes := 0
+	db = sqltest.OpenWithHook(func(query string) {
+		if !strings.HasPrefix(query, "DELETE") {
+			return
+		}
+		deletes++
+		if deletes == 1 {
+			// another writer inserts the key right after the delete
+			_, err := db.ExecContext(ctx, "INSERT INTO entries (entry_key, value) VALUES ($1, $2)", "a", "other")
+			require.NoError(t, err)
+		}
+	})
+	defer db.Close()
+
+	require.NoError(t, table.Create(ctx, db, "entry_key VARCHAR(64) PRIMARY KEY", "value TEXT NOT NULL"))
+	require.NoError(t, table.Replace(ctx, db, []string{"entry_key", "value"}, "a", "mine"))
+	assert.Equal(t, 2, deletes)
+
+	var value string
+	require.NoError(t, db.QueryRowContext(ctx, table.Query("SELECT value FROM %s WHERE entry_key = ?"), "a").Scan(&value))
+	assert.Equal(t, "mine", value)
+}
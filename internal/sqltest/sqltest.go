@@ -0,0 +1,247 @@
+// Package sqltest is an in-memory database/sql driver for the tests of the
+// SQL stores, so they run without a database engine.
+//
+// It only understands the portable statements the stores use:
+//
+//	CREATE TABLE IF NOT EXISTS t (...)
+//	INSERT INTO t (a, b) VALUES (?, ?)
+//	DELETE FROM t WHERE a = ?
+//	SELECT a, b FROM t WHERE a = ?   (or a < ?)
+//
+// with ? or $n placeholders. The PRIMARY KEY column of a table is unique.
+// Transactions are not isolated: their statements apply immediately.
+package sqltest
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+var (
+	createRe = regexp.MustCompile(`(?i)^CREATE TABLE IF NOT EXISTS (\w+) \((.*)\)$`)
+	keyRe    = regexp.MustCompile(`(?i)(?:^|,)\s*(\w+) [^,]*PRIMARY KEY`)
+	insertRe = regexp.MustCompile(`(?i)^INSERT INTO (\w+) \(([^)]*)\) VALUES \(([^)]*)\)$`)
+	deleteRe = regexp.MustCompile(`(?i)^DELETE FROM (\w+) WHERE (\w+) (=|<) (\?|\$\d+)$`)
+	selectRe = regexp.MustCompile(`(?i)^SELECT (.+) FROM (\w+) WHERE (\w+) (=|<) (\?|\$\d+)$`)
+)
+
+// Open returns a new empty database.
+func Open() *sql.DB {
+	return OpenWithHook(nil)
+}
+
+// OpenWithHook returns a new empty database calling hook with each statement
+// after running it. The hook can use the database, to simulate concurrent
+// writers.
+func OpenWithHook(hook func(query string)) *sql.DB {
+	return sql.OpenDB(connector{db: &database{tables: map[string][]row{}, keys: map[string]string{}, hook: hook}})
+}
+
+type row map[string]driver.Value
+
+type database struct {
+	mu     sync.Mutex
+	tables map[string][]row
+	// keys are the primary key columns of the tables.
+	keys map[string]string
+	hook func(query string)
+}
+
+type connector struct {
+	db *database
+}
+
+func (c connector) Connect(context.Context) (driver.Conn, error) {
+	return &conn{db: c.db}, nil
+}
+
+func (c connector) Driver() driver.Driver {
+	return fakeDriver{}
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("sqltest: use sqltest.Open")
+}
+
+type conn struct {
+	db *database
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return &stmt{db: c.db, query: strings.Join(strings.Fields(query), " ")}, nil
+}
+
+func (c *conn) Close() error { return nil }
+
+func (c *conn) Begin() (driver.Tx, error) { return tx{}, nil }
+
+type tx struct{}
+
+func (tx) Commit() error { return nil }
+
+func (tx) Rollback() error { return nil }
+
+type stmt struct {
+	db    *database
+	query string
+}
+
+func (s *stmt) Close() error { return nil }
+
+func (s *stmt) NumInput() int { return -1 }
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	result, err := s.exec(args)
+	if s.db.hook != nil {
+		s.db.hook(s.query)
+	}
+	return result, err
+}
+
+func (s *stmt) exec(args []driver.Value) (driver.Result, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	if m := createRe.FindStringSubmatch(s.query); m != nil {
+		if _, ok := s.db.tables[m[1]]; !ok {
+			s.db.tables[m[1]] = nil
+			if key := keyRe.FindStringSubmatch(m[2]); key != nil {
+				s.db.keys[m[1]] = key[1]
+			}
+		}
+		return driver.RowsAffected(0), nil
+	}
+
+	if m := insertRe.FindStringSubmatch(s.query); m != nil {
+		rows, err := s.db.table(m[1])
+		if err != nil {
+			return nil, err
+		}
+		columns := splitList(m[2])
+		if len(columns) != len(args) || len(splitList(m[3])) != len(args) {
+			return nil, fmt.Errorf("sqltest: %d arguments for %q", len(args), s.query)
+		}
+		r := row{}
+		for i, column := range columns {
+			r[column] = args[i]
+		}
+		if key, ok := s.db.keys[m[1]]; ok {
+			for _, existing := range rows {
+				if fmt.Sprint(existing[key]) == fmt.Sprint(r[key]) {
+					return nil, fmt.Errorf("sqltest: duplicate %s %v", key, r[key])
+				}
+			}
+		}
+		s.db.tables[m[1]] = append(rows, r)
+		return driver.RowsAffected(1), nil
+	}
+
+	if m := deleteRe.FindStringSubmatch(s.query); m != nil {
+		rows, err := s.db.table(m[1])
+		if err != nil {
+			return nil, err
+		}
+		var kept []row
+		for _, r := range rows {
+			match, err := matches(r[m[2]], m[3], args)
+			if err != nil {
+				return nil, err
+			}
+			if !match {
+				kept = append(kept, r)
+			}
+		}
+		s.db.tables[m[1]] = kept
+		return driver.RowsAffected(len(rows) - len(kept)), nil
+	}
+
+	return nil, fmt.Errorf("sqltest: unsupported statement %q", s.query)
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	m := selectRe.FindStringSubmatch(s.query)
+	if m == nil {
+		return nil, fmt.Errorf("sqltest: unsupported query %q", s.query)
+	}
+	rows, err := s.db.table(m[2])
+	if err != nil {
+		return nil, err
+	}
+
+	result := &resultRows{columns: splitList(m[1])}
+	for _, r := range rows {
+		match, err := matches(r[m[3]], m[4], args)
+		if err != nil {
+			return nil, err
+		}
+		if match {
+			result.rows = append(result.rows, r)
+		}
+	}
+	return result, nil
+}
+
+func (db *database) table(name string) ([]row, error) {
+	rows, ok := db.tables[name]
+	if !ok {
+		return nil, fmt.Errorf("sqltest: no such table %s", name)
+	}
+	return rows, nil
+}
+
+func matches(value driver.Value, op string, args []driver.Value) (bool, error) {
+	if len(args) != 1 {
+		return false, fmt.Errorf("sqltest: %d arguments for one placeholder", len(args))
+	}
+	if op == "=" {
+		return fmt.Sprint(value) == fmt.Sprint(args[0]), nil
+	}
+
+	a, ok1 := value.(int64)
+	b, ok2 := args[0].(int64)
+	if !ok1 || !ok2 {
+		return false, fmt.Errorf("sqltest: < only compares integers")
+	}
+	return a < b, nil
+}
+
+func splitList(list string) []string {
+	parts := strings.Split(list, ",")
+	for i, part := range parts {
+		parts[i] = strings.TrimSpace(part)
+	}
+	return parts
+}
+
+type resultRows struct {
+	columns []string
+	rows    []row
+	next    int
+}
+
+func (r *resultRows) Columns() []string { return r.columns }
+
+func (r *resultRows) Close() error { return nil }
+
+func (r *resultRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.rows) {
+		return io.EOF
+	}
+	for i, column := range r.columns {
+		dest[i] = r.rows[r.next][column]
+	}
+	r.next++
+	return nil
+}
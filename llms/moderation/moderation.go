@@ -0,0 +1,295 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/devalexandre/mylangchaingo/llms/openai"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// ErrFlagged is wrapped by every *ViolationError.
+var ErrFlagged = errors.New("content flagged by moderation")
+
+// Stage tells whether a violation was found in the model input or output.
+type Stage string
+
+const (
+	StageInput  Stage = "input"
+	StageOutput Stage = "output"
+)
+
+// Violation describes a text that crossed the moderation thresholds.
+type Violation struct {
+	Stage Stage
+	// Index is the index of the message (input) or of the choice (output).
+	Index      int
+	Categories []string
+	Scores     map[string]float64
+}
+
+// ViolationError is returned under PolicyBlock when a text is flagged.
+type ViolationError struct {
+	Violations []Violation
+}
+
+func (e *ViolationError) Error() string {
+	parts := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		parts = append(parts, fmt.Sprintf("%s %d: %s", v.Stage, v.Index, strings.Join(v.Categories, ", ")))
+	}
+	return fmt.Sprintf("%s (%s)", ErrFlagged, strings.Join(parts, "; "))
+}
+
+func (e *ViolationError) Unwrap() error {
+	return ErrFlagged
+}
+
+// Moderator screens texts. Results must be in the same order as the inputs.
+type Moderator interface {
+	Moderate(ctx context.Context, inputs []string) ([]openai.ModerationResult, error)
+}
+
+// ModeratorFunc is an adapter to allow the use of ordinary functions as Moderators.
+type ModeratorFunc func(ctx context.Context, inputs []string) ([]openai.ModerationResult, error)
+
+// Moderate calls f(ctx, inputs).
+func (f ModeratorFunc) Moderate(ctx context.Context, inputs []string) ([]openai.ModerationResult, error) {
+	return f(ctx, inputs)
+}
+
+// OpenAI returns a Moderator backed by the OpenAI moderations API.
+func OpenAI(llm *openai.LLM, opts ...openai.ModerationOption) Moderator {
+	return ModeratorFunc(func(ctx context.Context, inputs []string) ([]openai.ModerationResult, error) {
+		return llm.Moderate(ctx, inputs, opts...)
+	})
+}
+
+// LLM is an llms.Model that screens the user messages before, and the
+// generated choices after, calling the wrapped model.
+type LLM struct {
+	model     llms.Model
+	moderator Moderator
+	options   options
+}
+
+var _ llms.Model = (*LLM)(nil)
+
+// New wraps model with a moderation guardrail.
+func New(model llms.Model, moderator Moderator, opts ...Option) *LLM {
+	return &LLM{
+		model:     model,
+		moderator: moderator,
+		options:   applyOptions(opts...),
+	}
+}
+
+// Call implements the call interface for LLM.
+func (l *LLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, l, prompt, options...)
+}
+
+// GenerateContent implements the Model interface.
+// Note that when streaming, chunks reach the streaming func before the output is screened.
+func (l *LLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { //nolint:lll
+	var inputViolations []Violation
+	if l.options.checkInput {
+		var err error
+		messages, inputViolations, err = l.screenInput(ctx, messages)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := l.model.GenerateContent(ctx, messages, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	var outputViolations []Violation
+	if l.options.checkOutput {
+		outputViolations, err = l.screenOutput(ctx, resp)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if l.options.policy == PolicyAnnotate && len(inputViolations)+len(outputViolations) > 0 {
+		annotate(resp, inputViolations, outputViolations)
+	}
+
+	return resp, nil
+}
+
+// screenInput moderates the text parts of the human messages. Under
+// PolicyRedact it returns a copy of messages with the flagged parts replaced.
+func (l *LLM) screenInput(ctx context.Context, messages []llms.MessageContent) ([]llms.MessageContent, []Violation, error) { //nolint:lll
+	type ref struct{ message, part int }
+
+	var texts []string
+	var refs []ref
+	for i, mc := range messages {
+		if mc.Role != llms.ChatMessageTypeHuman && mc.Role != llms.ChatMessageTypeGeneric {
+			continue
+		}
+		for j, part := range mc.Parts {
+			if text, ok := part.(llms.TextContent); ok && text.Text != "" {
+				texts = append(texts, text.Text)
+				refs = append(refs, ref{message: i, part: j})
+			}
+		}
+	}
+	if len(texts) == 0 {
+		return messages, nil, nil
+	}
+
+	results, err := l.moderate(ctx, texts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var violations []Violation
+	var redacted []llms.MessageContent
+	for k, res := range results {
+		categories, scores := l.options.flagged(res)
+		if len(categories) == 0 {
+			continue
+		}
+		violations = append(violations, Violation{
+			Stage:      StageInput,
+			Index:      refs[k].message,
+			Categories: categories,
+			Scores:     scores,
+		})
+
+		if l.options.policy == PolicyRedact {
+			if redacted == nil {
+				redacted = copyMessages(messages)
+			}
+			redacted[refs[k].message].Parts[refs[k].part] = llms.TextContent{Text: l.options.redaction}
+		}
+	}
+
+	if len(violations) > 0 && l.options.policy == PolicyBlock {
+		return nil, nil, &ViolationError{Violations: violations}
+	}
+	if redacted != nil {
+		return redacted, violations, nil
+	}
+	return messages, violations, nil
+}
+
+// screenOutput moderates the choices of resp, redacting them in place under PolicyRedact.
+func (l *LLM) screenOutput(ctx context.Context, resp *llms.ContentResponse) ([]Violation, error) {
+	var texts []string
+	var indexes []int
+	for i, choice := range resp.Choices {
+		if choice.Content != "" {
+			texts = append(texts, choice.Content)
+			indexes = append(indexes, i)
+		}
+	}
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	results, err := l.moderate(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+
+	var violations []Violation
+	for k, res := range results {
+		categories, scores := l.options.flagged(res)
+		if len(categories) == 0 {
+			continue
+		}
+		violations = append(violations, Violation{
+			Stage:      StageOutput,
+			Index:      indexes[k],
+			Categories: categories,
+			Scores:     scores,
+		})
+		if l.options.policy == PolicyRedact {
+			resp.Choices[indexes[k]].Content = l.options.redaction
+		}
+	}
+
+	if len(violations) > 0 && l.options.policy == PolicyBlock {
+		return nil, &ViolationError{Violations: violations}
+	}
+	return violations, nil
+}
+
+func (l *LLM) moderate(ctx context.Context, texts []string) ([]openai.ModerationResult, error) {
+	results, err := l.moderator.Moderate(ctx, texts)
+	if err != nil {
+		return nil, fmt.Errorf("moderate: %w", err)
+	}
+	if len(results) != len(texts) {
+		return nil, fmt.Errorf("moderate: expected %d results, got %d", len(texts), len(results))
+	}
+	return results, nil
+}
+
+// flagged returns the categories of res that violate the thresholds, sorted by name.
+func (o options) flagged(res openai.ModerationResult) ([]string, map[string]float64) {
+	var categories []string
+	scores := map[string]float64{}
+
+	seen := map[string]bool{}
+	for category, score := range res.CategoryScores {
+		seen[category] = true
+		threshold, ok := o.thresholds[category]
+		if !ok && o.defaultThreshold > 0 {
+			threshold, ok = o.defaultThreshold, true
+		}
+		if (ok && score >= threshold) || (!ok && res.Categories[category]) {
+			categories = append(categories, category)
+			scores[category] = score
+		}
+	}
+	// categories flagged by the API without a score.
+	for category, isFlagged := range res.Categories {
+		if isFlagged && !seen[category] {
+			categories = append(categories, category)
+		}
+	}
+
+	sort.Strings(categories)
+	return categories, scores
+}
+
+// annotate adds the violations to the GenerationInfo of the choices. Every
+// choice carries the input violations plus its own output violation.
+func annotate(resp *llms.ContentResponse, input, output []Violation) {
+	for i, choice := range resp.Choices {
+		violations := append([]Violation{}, input...)
+		for _, v := range output {
+			if v.Index == i {
+				violations = append(violations, v)
+			}
+		}
+		if len(violations) == 0 {
+			continue
+		}
+		if choice.GenerationInfo == nil {
+			choice.GenerationInfo = map[string]any{}
+		}
+		choice.GenerationInfo["ModerationViolations"] = violations
+	}
+}
+
+func copyMessages(messages []llms.MessageContent) []llms.MessageContent {
+	out := make([]llms.MessageContent, len(messages))
+	for i, mc := range messages {
+		out[i] = llms.MessageContent{
+			Role:  mc.Role,
+			Parts: append([]llms.ContentPart{}, mc.Parts...),
+		}
+	}
+	return out
+}
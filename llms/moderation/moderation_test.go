@@ -0,0 +1,118 @@
+package moderation
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/devalexandre/mylangchaingo/llms/fake"
+	"github.com/devalexandre/mylangchaingo/llms/openai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// keywordModerator scores texts containing "attack" as violent.
+func keywordModerator() Moderator {
+	return ModeratorFunc(func(_ context.Context, inputs []string) ([]openai.ModerationResult, error) {
+		results := make([]openai.ModerationResult, len(inputs))
+		for i, in := range inputs {
+			score := 0.01
+			if strings.Contains(in, "attack") {
+				score = 0.6
+			}
+			results[i] = openai.ModerationResult{
+				Flagged:        score > 0.5,
+				Categories:     map[string]bool{"violence": score > 0.5, "hate": false},
+				CategoryScores: map[string]float64{"violence": score, "hate": 0.01},
+			}
+		}
+		return results, nil
+	})
+}
+
+// recordingLLM records the messages it receives.
+type recordingLLM struct {
+	*fake.LLM
+	messages []llms.MessageContent
+}
+
+func (r *recordingLLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { //nolint:lll
+	r.messages = messages
+	return r.LLM.GenerateContent(ctx, messages, options...)
+}
+
+func TestBlockInput(t *testing.T) {
+	t.Parallel()
+	llm := New(fake.NewFakeLLM([]string{"ok"}), keywordModerator())
+
+	_, err := llm.Call(context.Background(), "plan an attack")
+	require.ErrorIs(t, err, ErrFlagged)
+
+	var verr *ViolationError
+	require.ErrorAs(t, err, &verr)
+	require.Len(t, verr.Violations, 1)
+	assert.Equal(t, StageInput, verr.Violations[0].Stage)
+	assert.Equal(t, []string{"violence"}, verr.Violations[0].Categories)
+}
+
+func TestBlockOutput(t *testing.T) {
+	t.Parallel()
+	llm := New(fake.NewFakeLLM([]string{"let's attack"}), keywordModerator())
+
+	_, err := llm.Call(context.Background(), "hello")
+	var verr *ViolationError
+	require.ErrorAs(t, err, &verr)
+	assert.Equal(t, StageOutput, verr.Violations[0].Stage)
+}
+
+func TestThresholds(t *testing.T) {
+	t.Parallel()
+	llm := New(fake.NewFakeLLM([]string{"ok"}), keywordModerator(), WithThreshold("violence", 0.8))
+
+	out, err := llm.Call(context.Background(), "plan an attack")
+	require.NoError(t, err)
+	assert.Equal(t, "ok", out)
+
+	llm = New(fake.NewFakeLLM([]string{"ok"}), keywordModerator(), WithDefaultThreshold(0.001))
+	_, err = llm.Call(context.Background(), "hello")
+	require.ErrorIs(t, err, ErrFlagged)
+}
+
+func TestRedact(t *testing.T) {
+	t.Parallel()
+	model := &recordingLLM{LLM: fake.NewFakeLLM([]string{"we attack at dawn"})}
+	llm := New(model, keywordModerator(), WithPolicy(PolicyRedact), WithRedaction("***"))
+
+	messages := []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeSystem, "you are an attack planner"),
+		llms.TextParts(llms.ChatMessageTypeHuman, "hi", "plan an attack"),
+	}
+	resp, err := llm.GenerateContent(context.Background(), messages)
+	require.NoError(t, err)
+
+	assert.Equal(t, "***", resp.Choices[0].Content)
+	// system messages are not screened.
+	assert.Equal(t, messages[0], model.messages[0])
+	assert.Equal(t, llms.TextContent{Text: "hi"}, model.messages[1].Parts[0])
+	assert.Equal(t, llms.TextContent{Text: "***"}, model.messages[1].Parts[1])
+	// the caller's messages are left untouched.
+	assert.Equal(t, llms.TextContent{Text: "plan an attack"}, messages[1].Parts[1])
+}
+
+func TestAnnotate(t *testing.T) {
+	t.Parallel()
+	llm := New(fake.NewFakeLLM([]string{"we attack at dawn"}), keywordModerator(), WithPolicy(PolicyAnnotate))
+
+	resp, err := llm.GenerateContent(context.Background(), []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "plan an attack"),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "we attack at dawn", resp.Choices[0].Content)
+	violations, ok := resp.Choices[0].GenerationInfo["ModerationViolations"].([]Violation)
+	require.True(t, ok)
+	require.Len(t, violations, 2)
+	assert.Equal(t, StageInput, violations[0].Stage)
+	assert.Equal(t, StageOutput, violations[1].Stage)
+}
@@ -0,0 +1,101 @@
+package moderation
+
+const (
+	_defaultRedaction = "[redacted]"
+)
+
+// Policy is what the guardrail does when a text violates the moderation thresholds.
+type Policy int
+
+const (
+	// PolicyBlock stops the call and returns a *ViolationError.
+	PolicyBlock Policy = iota
+	// PolicyRedact replaces the offending text and carries on.
+	PolicyRedact
+	// PolicyAnnotate carries on unchanged and reports the violations in the
+	// GenerationInfo of the returned choices.
+	PolicyAnnotate
+)
+
+type options struct {
+	policy           Policy
+	thresholds       map[string]float64
+	defaultThreshold float64
+	redaction        string
+	checkInput       bool
+	checkOutput      bool
+}
+
+// Option is a function type that can be used to modify the guardrail.
+type Option func(*options)
+
+// WithPolicy sets what happens when a text is flagged. Defaults to PolicyBlock.
+func WithPolicy(policy Policy) Option {
+	return func(o *options) {
+		o.policy = policy
+	}
+}
+
+// WithThreshold sets the minimum score at which a category, e.g. "violence"
+// or "self-harm/intent", is considered a violation.
+func WithThreshold(category string, score float64) Option {
+	return func(o *options) {
+		o.thresholds[category] = score
+	}
+}
+
+// WithThresholds sets the minimum scores for several categories at once.
+func WithThresholds(thresholds map[string]float64) Option {
+	return func(o *options) {
+		for category, score := range thresholds {
+			o.thresholds[category] = score
+		}
+	}
+}
+
+// WithDefaultThreshold sets the minimum score used for the categories without
+// an explicit threshold. When not set, those categories follow the flags
+// returned by the moderation API.
+func WithDefaultThreshold(score float64) Option {
+	return func(o *options) {
+		o.defaultThreshold = score
+	}
+}
+
+// WithRedaction sets the text that replaces flagged content under PolicyRedact.
+// Defaults to "[redacted]".
+func WithRedaction(text string) Option {
+	return func(o *options) {
+		o.redaction = text
+	}
+}
+
+// WithInputCheck enables or disables screening the messages sent to the model.
+// Enabled by default.
+func WithInputCheck(check bool) Option {
+	return func(o *options) {
+		o.checkInput = check
+	}
+}
+
+// WithOutputCheck enables or disables screening the choices returned by the model.
+// Enabled by default.
+func WithOutputCheck(check bool) Option {
+	return func(o *options) {
+		o.checkOutput = check
+	}
+}
+
+func applyOptions(opts ...Option) options {
+	o := options{
+		policy:      PolicyBlock,
+		thresholds:  map[string]float64{},
+		redaction:   _defaultRedaction,
+		checkInput:  true,
+		checkOutput: true,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
@@ -0,0 +1,115 @@
+package openai
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/devalexandre/mylangchaingo/llms/openai/internal/openaiclient"
+	"github.com/tmc/langchaingo/llms"
+)
+
+const (
+	// BatchEndpointChat is the batch endpoint of chat completions.
+	BatchEndpointChat = "/v1/chat/completions"
+	// BatchEndpointEmbeddings is the batch endpoint of embeddings.
+	BatchEndpointEmbeddings = "/v1/embeddings"
+
+	// FilePurposeBatch is the purpose of batch input files.
+	FilePurposeBatch = "batch"
+)
+
+// File is a file stored with the Files API.
+type File = openaiclient.File
+
+// Batch is a batch job of the Batch API.
+type Batch = openaiclient.Batch
+
+// BatchRequest is a single line of a batch input file.
+type BatchRequest struct {
+	CustomID string `json:"custom_id"`
+	Method   string `json:"method"`
+	URL      string `json:"url"`
+	Body     any    `json:"body"`
+}
+
+// NewChatBatchRequest builds the batch request equivalent to calling
+// GenerateContent with the same messages and options.
+func (o *LLM) NewChatBatchRequest(customID string, messages []llms.MessageContent, options ...llms.CallOption) (BatchRequest, error) { //nolint:lll
+	opts := llms.CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	req, err := newChatRequest(messages, opts)
+	if err != nil {
+		return BatchRequest{}, err
+	}
+	req.Model = o.client.ChatModel(req.Model)
+	req.StreamingFunc = nil
+
+	return BatchRequest{
+		CustomID: customID,
+		Method:   http.MethodPost,
+		URL:      BatchEndpointChat,
+		Body:     req,
+	}, nil
+}
+
+// NewEmbeddingBatchRequest builds the batch request equivalent to calling
+// CreateEmbedding with the same texts.
+func (o *LLM) NewEmbeddingBatchRequest(customID string, texts []string) BatchRequest {
+	return BatchRequest{
+		CustomID: customID,
+		Method:   http.MethodPost,
+		URL:      BatchEndpointEmbeddings,
+		Body: openaiclient.EmbeddingRequest{
+			Model: o.client.EmbeddingModel(),
+			Input: texts,
+		},
+	}
+}
+
+// UploadFile uploads a file with the given purpose, streaming it from r.
+func (o *LLM) UploadFile(ctx context.Context, filename, purpose string, r io.Reader) (*File, error) {
+	return o.client.UploadFile(ctx, &openaiclient.FileUploadRequest{
+		Filename: filename,
+		Purpose:  purpose,
+		Content:  r,
+	})
+}
+
+// RetrieveFile returns the metadata of a file.
+func (o *LLM) RetrieveFile(ctx context.Context, fileID string) (*File, error) {
+	return o.client.RetrieveFile(ctx, fileID)
+}
+
+// FileContent returns the content of a file. The caller must close it.
+func (o *LLM) FileContent(ctx context.Context, fileID string) (io.ReadCloser, error) {
+	return o.client.FileContent(ctx, fileID)
+}
+
+// DeleteFile deletes a file.
+func (o *LLM) DeleteFile(ctx context.Context, fileID string) error {
+	return o.client.DeleteFile(ctx, fileID)
+}
+
+// CreateBatch creates a batch for an uploaded input file. The endpoint is
+// BatchEndpointChat or BatchEndpointEmbeddings.
+func (o *LLM) CreateBatch(ctx context.Context, inputFileID, endpoint string, metadata map[string]string) (*Batch, error) {
+	return o.client.CreateBatch(ctx, &openaiclient.BatchRequest{
+		InputFileID: inputFileID,
+		Endpoint:    endpoint,
+		Metadata:    metadata,
+	})
+}
+
+// RetrieveBatch returns the current state of a batch.
+func (o *LLM) RetrieveBatch(ctx context.Context, batchID string) (*Batch, error) {
+	return o.client.RetrieveBatch(ctx, batchID)
+}
+
+// CancelBatch cancels an in-progress batch.
+func (o *LLM) CancelBatch(ctx context.Context, batchID string) (*Batch, error) {
+	return o.client.CancelBatch(ctx, batchID)
+}
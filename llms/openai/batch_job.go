@@ -0,0 +1,505 @@
+package openai
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/devalexandre/mylangchaingo/internal/atomicfile"
+	"github.com/devalexandre/mylangchaingo/llms/openai/internal/openaiclient"
+	"github.com/tmc/langchaingo/llms"
+)
+
+const (
+	_defaultBatchPollInterval = 30 * time.Second
+	maxBatchRequests          = 50000
+)
+
+var (
+	ErrBatchEmpty          = errors.New("batch has no requests")
+	ErrBatchTooLarge       = fmt.Errorf("batch exceeds %d requests", maxBatchRequests)
+	ErrBatchMixedEndpoints = errors.New("all requests of a batch must target the same endpoint")
+	ErrBatchDuplicateID    = errors.New("duplicate batch custom_id")
+	ErrBatchFailed         = errors.New("batch failed")
+	ErrBatchNoResult       = errors.New("batch finished without a result for this request")
+)
+
+// BatchStatus values reported by the Batch API.
+const (
+	BatchStatusValidating = "validating"
+	BatchStatusFailed     = "failed"
+	BatchStatusInProgress = "in_progress"
+	BatchStatusFinalizing = "finalizing"
+	BatchStatusCompleted  = "completed"
+	BatchStatusExpired    = "expired"
+	BatchStatusCancelling = "cancelling"
+	BatchStatusCancelled  = "cancelled"
+)
+
+// BatchResult is the result of a single request of a batch. Response is set
+// for chat requests and Embeddings for embedding requests; Err is set when
+// the request failed.
+type BatchResult struct {
+	CustomID   string
+	StatusCode int
+	Response   *llms.ContentResponse
+	Embeddings [][]float32
+	Err        error
+}
+
+// batchState is what a BatchJob persists to resume after a restart.
+type batchState struct {
+	BatchID      string `json:"batch_id"`
+	InputFileID  string `json:"input_file_id"`
+	Endpoint     string `json:"endpoint"`
+	Status       string `json:"status"`
+	OutputFileID string `json:"output_file_id,omitempty"`
+	ErrorFileID  string `json:"error_file_id,omitempty"`
+	// RequestsHash identifies the requests of the batch, so a state is only
+	// resumed by a job with the same requests.
+	RequestsHash string `json:"requests_hash,omitempty"`
+}
+
+// BatchJob runs chat or embedding requests through the Batch API: it writes
+// the requests to a JSONL file, uploads it, creates the batch, polls it until
+// it finishes and maps the results back to the requests by custom_id.
+//
+// When a state path is given, the job state is saved there after every step,
+// so a job interrupted while the batch is running is resumed by running a job
+// with the same state path, and the same requests or none, instead of
+// submitting the requests again. The state is deleted once the results are
+// downloaded.
+type BatchJob struct {
+	llm          *LLM
+	statePath    string
+	pollInterval time.Duration
+	metadata     map[string]string
+
+	requests []BatchRequest
+	ids      map[string]struct{}
+	state    *batchState
+}
+
+// BatchJobOption is a functional option for a BatchJob.
+type BatchJobOption func(*BatchJob)
+
+// WithBatchPollInterval sets how often the batch status is checked. Defaults to 30s.
+func WithBatchPollInterval(interval time.Duration) BatchJobOption {
+	return func(j *BatchJob) {
+		j.pollInterval = interval
+	}
+}
+
+// WithBatchMetadata sets the metadata of the created batch.
+func WithBatchMetadata(metadata map[string]string) BatchJobOption {
+	return func(j *BatchJob) {
+		j.metadata = metadata
+	}
+}
+
+// NewBatchJob creates a batch job that persists its state to statePath.
+// Pass an empty statePath to keep the state in memory only.
+func (o *LLM) NewBatchJob(statePath string, opts ...BatchJobOption) *BatchJob {
+	j := &BatchJob{
+		llm:          o,
+		statePath:    statePath,
+		pollInterval: _defaultBatchPollInterval,
+		ids:          map[string]struct{}{},
+	}
+	for _, opt := range opts {
+		opt(j)
+	}
+	return j
+}
+
+// Add adds a request to the job.
+func (j *BatchJob) Add(req BatchRequest) error {
+	if _, ok := j.ids[req.CustomID]; ok {
+		return fmt.Errorf("%w: %s", ErrBatchDuplicateID, req.CustomID)
+	}
+	if len(j.requests) > 0 && j.requests[0].URL != req.URL {
+		return ErrBatchMixedEndpoints
+	}
+	if len(j.requests) == maxBatchRequests {
+		return ErrBatchTooLarge
+	}
+	j.ids[req.CustomID] = struct{}{}
+	j.requests = append(j.requests, req)
+	return nil
+}
+
+// AddChat adds a chat completion request to the job.
+func (j *BatchJob) AddChat(customID string, messages []llms.MessageContent, options ...llms.CallOption) error {
+	req, err := j.llm.NewChatBatchRequest(customID, messages, options...)
+	if err != nil {
+		return err
+	}
+	return j.Add(req)
+}
+
+// AddEmbedding adds an embedding request to the job.
+func (j *BatchJob) AddEmbedding(customID string, texts []string) error {
+	return j.Add(j.llm.NewEmbeddingBatchRequest(customID, texts))
+}
+
+// Run submits the job, or resumes it from the saved state, waits for the
+// batch to finish and returns its results. Results follow the order in which
+// requests were added; when resuming without re-adding the requests they
+// follow the order of the output file.
+func (j *BatchJob) Run(ctx context.Context) ([]BatchResult, error) {
+	if _, err := j.Submit(ctx); err != nil {
+		return nil, err
+	}
+
+	batch, err := j.Wait(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if batch.Status == BatchStatusFailed {
+		// a failed batch is not resumed, so the next run submits the requests again
+		if err := j.deleteState(); err != nil {
+			return nil, err
+		}
+		return nil, batchFailure(batch)
+	}
+
+	return j.Results(ctx)
+}
+
+// Submit uploads the requests and creates the batch. If a state was saved
+// by a previous run of the same requests, the existing batch is returned
+// instead; a state saved for other requests, or for a batch that failed,
+// expired or was cancelled, is ignored.
+func (j *BatchJob) Submit(ctx context.Context) (*Batch, error) {
+	if err := j.loadState(); err != nil {
+		return nil, err
+	}
+
+	var hash string
+	if len(j.requests) > 0 {
+		var err error
+		if hash, err = j.requestsHash(); err != nil {
+			return nil, err
+		}
+	}
+	if j.state != nil && (hash == "" || hash == j.state.RequestsHash) {
+		batch, err := j.llm.RetrieveBatch(ctx, j.state.BatchID)
+		if err != nil {
+			return nil, err
+		}
+		if !isBatchAbandoned(batch.Status) {
+			return batch, nil
+		}
+		if err := j.deleteState(); err != nil {
+			return nil, err
+		}
+	}
+	j.state = nil
+
+	if len(j.requests) == 0 {
+		return nil, ErrBatchEmpty
+	}
+
+	endpoint := j.requests[0].URL
+	file, err := j.llm.UploadFile(ctx, "batch.jsonl", FilePurposeBatch, j.encodeRequests())
+	if err != nil {
+		return nil, fmt.Errorf("upload batch input: %w", err)
+	}
+
+	batch, err := j.llm.CreateBatch(ctx, file.ID, endpoint, j.metadata)
+	if err != nil {
+		return nil, fmt.Errorf("create batch: %w", err)
+	}
+
+	j.state = &batchState{
+		BatchID:      batch.ID,
+		InputFileID:  file.ID,
+		Endpoint:     endpoint,
+		RequestsHash: hash,
+	}
+	if err := j.update(batch); err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+// Wait polls the batch until it completes, fails, expires or is cancelled.
+func (j *BatchJob) Wait(ctx context.Context) (*Batch, error) {
+	if j.state == nil {
+		return nil, ErrBatchEmpty
+	}
+	for {
+		batch, err := j.llm.RetrieveBatch(ctx, j.state.BatchID)
+		if err != nil {
+			return nil, fmt.Errorf("retrieve batch: %w", err)
+		}
+		if err := j.update(batch); err != nil {
+			return nil, err
+		}
+		if isBatchFinished(batch.Status) {
+			return batch, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(j.pollInterval):
+		}
+	}
+}
+
+// Results downloads the output and error files of a finished batch and maps
+// them back to the requests, then deletes the saved state.
+func (j *BatchJob) Results(ctx context.Context) ([]BatchResult, error) {
+	if j.state == nil {
+		return nil, ErrBatchEmpty
+	}
+
+	var results []BatchResult
+	for _, fileID := range []string{j.state.OutputFileID, j.state.ErrorFileID} {
+		if fileID == "" {
+			continue
+		}
+		fileResults, err := j.readResults(ctx, fileID)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, fileResults...)
+	}
+	if err := j.deleteState(); err != nil {
+		return nil, err
+	}
+
+	if len(j.requests) == 0 {
+		return results, nil
+	}
+
+	byID := make(map[string]BatchResult, len(results))
+	for _, r := range results {
+		byID[r.CustomID] = r
+	}
+	ordered := make([]BatchResult, len(j.requests))
+	for i, req := range j.requests {
+		r, ok := byID[req.CustomID]
+		if !ok {
+			r = BatchResult{CustomID: req.CustomID, Err: ErrBatchNoResult}
+		}
+		ordered[i] = r
+	}
+	return ordered, nil
+}
+
+// encodeRequests streams the requests as JSONL.
+func (j *BatchJob) encodeRequests() io.Reader {
+	pr, pw := io.Pipe()
+	go func() {
+		w := bufio.NewWriter(pw)
+		enc := json.NewEncoder(w)
+		for _, req := range j.requests {
+			if err := enc.Encode(req); err != nil {
+				pw.CloseWithError(fmt.Errorf("encode request %s: %w", req.CustomID, err))
+				return
+			}
+		}
+		pw.CloseWithError(w.Flush())
+	}()
+	return pr
+}
+
+// requestsHash hashes the JSONL encoding of the requests.
+func (j *BatchJob) requestsHash() (string, error) {
+	h := sha256.New()
+	enc := json.NewEncoder(h)
+	for _, req := range j.requests {
+		if err := enc.Encode(req); err != nil {
+			return "", fmt.Errorf("encode request %s: %w", req.CustomID, err)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// batchOutputLine is a line of a batch output or error file.
+type batchOutputLine struct {
+	CustomID string `json:"custom_id"`
+	Response *struct {
+		StatusCode int             `json:"status_code"`
+		Body       json.RawMessage `json:"body"`
+	} `json:"response"`
+	Error *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (j *BatchJob) readResults(ctx context.Context, fileID string) ([]BatchResult, error) {
+	content, err := j.llm.FileContent(ctx, fileID)
+	if err != nil {
+		return nil, fmt.Errorf("download batch file %s: %w", fileID, err)
+	}
+	defer content.Close()
+
+	var results []BatchResult
+	dec := json.NewDecoder(content)
+	for {
+		var line batchOutputLine
+		if err := dec.Decode(&line); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("decode batch file %s: %w", fileID, err)
+		}
+		results = append(results, j.parseResult(line))
+	}
+	return results, nil
+}
+
+func (j *BatchJob) parseResult(line batchOutputLine) BatchResult {
+	result := BatchResult{CustomID: line.CustomID}
+	if line.Error != nil {
+		result.Err = fmt.Errorf("%s: %s", line.Error.Code, line.Error.Message) // nolint:goerr113
+		return result
+	}
+	if line.Response == nil {
+		result.Err = ErrBatchNoResult
+		return result
+	}
+
+	result.StatusCode = line.Response.StatusCode
+	if result.StatusCode != 200 {
+		var errResp struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		_ = json.Unmarshal(line.Response.Body, &errResp)
+		result.Err = fmt.Errorf("API returned unexpected status code: %d: %s", result.StatusCode, errResp.Error.Message) // nolint:goerr113,lll
+		return result
+	}
+
+	switch j.state.Endpoint {
+	case BatchEndpointEmbeddings:
+		result.Embeddings, result.Err = decodeBatchEmbeddings(line.Response.Body)
+	default:
+		var chat openaiclient.ChatCompletionResponse
+		if err := json.Unmarshal(line.Response.Body, &chat); err != nil {
+			result.Err = fmt.Errorf("decode chat response: %w", err)
+			return result
+		}
+		result.Response, result.Err = contentResponseFromChat(&chat)
+	}
+	return result
+}
+
+func decodeBatchEmbeddings(body []byte) ([][]float32, error) {
+	var resp struct {
+		Data []struct {
+			Embedding []float32 `json:"embedding"`
+			Index     int       `json:"index"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("decode embedding response: %w", err)
+	}
+	if len(resp.Data) == 0 {
+		return nil, ErrEmptyResponse
+	}
+
+	sort.Slice(resp.Data, func(a, b int) bool { return resp.Data[a].Index < resp.Data[b].Index })
+	embeddings := make([][]float32, len(resp.Data))
+	for i, d := range resp.Data {
+		embeddings[i] = d.Embedding
+	}
+	return embeddings, nil
+}
+
+// update records the batch status and saves the state.
+func (j *BatchJob) update(batch *Batch) error {
+	j.state.Status = batch.Status
+	j.state.OutputFileID = batch.OutputFileID
+	j.state.ErrorFileID = batch.ErrorFileID
+	return j.saveState()
+}
+
+func (j *BatchJob) loadState() error {
+	if j.state != nil || j.statePath == "" {
+		return nil
+	}
+	data, err := os.ReadFile(j.statePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read batch state: %w", err)
+	}
+
+	var state batchState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("decode batch state: %w", err)
+	}
+	j.state = &state
+	return nil
+}
+
+func (j *BatchJob) deleteState() error {
+	if j.statePath == "" {
+		return nil
+	}
+	if err := os.Remove(j.statePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("delete batch state: %w", err)
+	}
+	return nil
+}
+
+// saveState writes the state atomically, so an interrupted write never leaves a corrupt file behind.
+func (j *BatchJob) saveState() error {
+	if j.statePath == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(j.state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := atomicfile.Write(j.statePath, data); err != nil {
+		return fmt.Errorf("save batch state: %w", err)
+	}
+	return nil
+}
+
+func isBatchFinished(status string) bool {
+	switch status {
+	case BatchStatusCompleted, BatchStatusFailed, BatchStatusExpired, BatchStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// isBatchAbandoned reports whether a batch finished without completing, so
+// its requests have to be submitted again.
+func isBatchAbandoned(status string) bool {
+	switch status {
+	case BatchStatusFailed, BatchStatusExpired, BatchStatusCancelled:
+		return true
+	}
+	return false
+}
+
+func batchFailure(batch *Batch) error {
+	if batch.Errors == nil || len(batch.Errors.Data) == 0 {
+		return fmt.Errorf("%w: %s", ErrBatchFailed, batch.ID)
+	}
+	msgs := make([]string, 0, len(batch.Errors.Data))
+	for _, e := range batch.Errors.Data {
+		msgs = append(msgs, fmt.Sprintf("line %d: %s", e.Line, e.Message))
+	}
+	return fmt.Errorf("%w: %s: %s", ErrBatchFailed, batch.ID, strings.Join(msgs, "; "))
+}
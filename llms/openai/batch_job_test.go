@@ -0,0 +1,227 @@
+package openai
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// fakeBatchAPI answers embedding batches, completing them on the second poll.
+type fakeBatchAPI struct {
+	t       *testing.T
+	mu      sync.Mutex
+	uploads int
+	batches int
+	polls   int
+	input   []BatchRequest
+	// failures is how many of the first batches fail.
+	failures int
+}
+
+func (f *fakeBatchAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/files":
+		f.uploads++
+		require.NoError(f.t, r.ParseMultipartForm(1<<20))
+		assert.Equal(f.t, FilePurposeBatch, r.FormValue("purpose"))
+		file, _, err := r.FormFile("file")
+		require.NoError(f.t, err)
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			var req BatchRequest
+			require.NoError(f.t, json.Unmarshal(scanner.Bytes(), &req))
+			f.input = append(f.input, req)
+		}
+		_, _ = io.WriteString(w, `{"id":"file-in","object":"file","purpose":"batch"}`)
+	case r.Method == http.MethodPost && r.URL.Path == "/batches":
+		var payload map[string]any
+		require.NoError(f.t, json.NewDecoder(r.Body).Decode(&payload))
+		assert.Equal(f.t, "file-in", payload["input_file_id"])
+		assert.Equal(f.t, BatchEndpointEmbeddings, payload["endpoint"])
+		f.batches++
+		_, _ = io.WriteString(w, `{"id":"batch-1","status":"validating"}`)
+	case r.Method == http.MethodGet && r.URL.Path == "/batches/batch-1":
+		if f.batches <= f.failures {
+			_, _ = io.WriteString(w, `{"id":"batch-1","status":"failed","errors":{"data":[{"line":1,"message":"invalid model"}]}}`)
+			return
+		}
+		f.polls++
+		if f.polls < 2 {
+			_, _ = io.WriteString(w, `{"id":"batch-1","status":"in_progress"}`)
+			return
+		}
+		_, _ = io.WriteString(w, `{"id":"batch-1","status":"completed","output_file_id":"file-out","error_file_id":"file-err"}`)
+	case r.Method == http.MethodGet && r.URL.Path == "/files/file-out/content":
+		// results are not in input order and embeddings not in index order.
+		fmt.Fprintln(w, `{"custom_id":"b","response":{"status_code":200,"body":{"data":[{"embedding":[2],"index":0}]}}}`)
+		fmt.Fprintln(w, `{"custom_id":"a","response":{"status_code":200,"body":{"data":[{"embedding":[1.2],"index":1},{"embedding":[1.1],"index":0}]}}}`)
+	case r.Method == http.MethodGet && r.URL.Path == "/files/file-err/content":
+		fmt.Fprintln(w, `{"custom_id":"c","response":{"status_code":400,"body":{"error":{"message":"too long"}}}}`)
+	default:
+		f.t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestBatchJobEmbeddings(t *testing.T) {
+	t.Parallel()
+
+	api := &fakeBatchAPI{t: t}
+	srv := httptest.NewServer(api)
+	defer srv.Close()
+
+	llm, err := New(WithToken("test-token"), WithBaseURL(srv.URL), WithEmbeddingModel("text-embedding-3-small"))
+	require.NoError(t, err)
+
+	statePath := filepath.Join(t.TempDir(), "batch.json")
+	job := llm.NewBatchJob(statePath, WithBatchPollInterval(time.Millisecond))
+	require.NoError(t, job.AddEmbedding("a", []string{"first", "second"}))
+	require.NoError(t, job.AddEmbedding("b", []string{"third"}))
+	require.NoError(t, job.AddEmbedding("c", []string{"fourth"}))
+	require.NoError(t, job.AddEmbedding("d", []string{"fifth"}))
+	require.ErrorIs(t, job.AddEmbedding("a", []string{"again"}), ErrBatchDuplicateID)
+
+	// a job with the same state file and requests resumes the batch instead of uploading again.
+	_, err = job.Submit(context.Background())
+	require.NoError(t, err)
+	resumed := llm.NewBatchJob(statePath, WithBatchPollInterval(time.Millisecond))
+	require.NoError(t, resumed.AddEmbedding("a", []string{"first", "second"}))
+	require.NoError(t, resumed.AddEmbedding("b", []string{"third"}))
+	require.NoError(t, resumed.AddEmbedding("c", []string{"fourth"}))
+	require.NoError(t, resumed.AddEmbedding("d", []string{"fifth"}))
+
+	results, err := resumed.Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, api.uploads)
+	assert.NoFileExists(t, statePath)
+
+	require.Len(t, api.input, 4)
+	body, ok := api.input[0].Body.(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "text-embedding-3-small", body["model"])
+
+	require.Len(t, results, 4)
+	assert.Equal(t, "a", results[0].CustomID)
+	assert.Equal(t, [][]float32{{1.1}, {1.2}}, results[0].Embeddings)
+	assert.Equal(t, [][]float32{{2}}, results[1].Embeddings)
+	require.Error(t, results[2].Err)
+	assert.Contains(t, results[2].Err.Error(), "too long")
+	require.ErrorIs(t, results[3].Err, ErrBatchNoResult)
+}
+
+func TestBatchJobIgnoresStateOfOtherRequests(t *testing.T) {
+	t.Parallel()
+
+	api := &fakeBatchAPI{t: t}
+	srv := httptest.NewServer(api)
+	defer srv.Close()
+
+	llm, err := New(WithToken("test-token"), WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	statePath := filepath.Join(t.TempDir(), "batch.json")
+	first := llm.NewBatchJob(statePath)
+	require.NoError(t, first.AddEmbedding("a", []string{"first"}))
+	_, err = first.Submit(context.Background())
+	require.NoError(t, err)
+
+	// an interrupted run leaves its state behind, a run of other requests submits them.
+	second := llm.NewBatchJob(statePath)
+	require.NoError(t, second.AddEmbedding("b", []string{"second"}))
+	_, err = second.Submit(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, api.uploads)
+	require.Len(t, api.input, 2)
+	assert.Equal(t, "b", api.input[1].CustomID)
+}
+
+func TestBatchJobRetriesFailedBatch(t *testing.T) {
+	t.Parallel()
+
+	api := &fakeBatchAPI{t: t, failures: 1}
+	srv := httptest.NewServer(api)
+	defer srv.Close()
+
+	llm, err := New(WithToken("test-token"), WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	statePath := filepath.Join(t.TempDir(), "batch.json")
+	newJob := func() *BatchJob {
+		job := llm.NewBatchJob(statePath, WithBatchPollInterval(time.Millisecond))
+		require.NoError(t, job.AddEmbedding("a", []string{"first"}))
+		return job
+	}
+
+	_, err = newJob().Run(context.Background())
+	require.ErrorIs(t, err, ErrBatchFailed)
+	assert.ErrorContains(t, err, "invalid model")
+	assert.NoFileExists(t, statePath)
+
+	// the retry submits a new batch instead of resuming the failed one
+	_, err = newJob().Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, api.uploads)
+	assert.Equal(t, 2, api.batches)
+}
+
+func TestBatchJobDiscardsStateOfFailedBatch(t *testing.T) {
+	t.Parallel()
+
+	api := &fakeBatchAPI{t: t, failures: 1}
+	srv := httptest.NewServer(api)
+	defer srv.Close()
+
+	llm, err := New(WithToken("test-token"), WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	// a run interrupted before the batch failed leaves its state behind
+	statePath := filepath.Join(t.TempDir(), "batch.json")
+	first := llm.NewBatchJob(statePath)
+	require.NoError(t, first.AddEmbedding("a", []string{"first"}))
+	_, err = first.Submit(context.Background())
+	require.NoError(t, err)
+
+	second := llm.NewBatchJob(statePath)
+	require.NoError(t, second.AddEmbedding("a", []string{"first"}))
+	_, err = second.Submit(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, api.batches)
+}
+
+func TestNewChatBatchRequest(t *testing.T) {
+	t.Parallel()
+
+	llm, err := New(WithToken("test-token"), WithModel("gpt-4o-mini"))
+	require.NoError(t, err)
+
+	req, err := llm.NewChatBatchRequest("row-1", []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeSystem, "classify the sentiment"),
+		llms.TextParts(llms.ChatMessageTypeHuman, "I love it"),
+	}, llms.WithMaxTokens(5))
+	require.NoError(t, err)
+
+	line, err := json.Marshal(req)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"custom_id":"row-1","method":"POST","url":"/v1/chat/completions",
+		"body":{"model":"gpt-4o-mini","temperature":0,"max_tokens":5,"messages":[
+			{"role":"system","content":"classify the sentiment"},
+			{"role":"user","content":"I love it"}
+		]}
+	}`, string(line))
+}
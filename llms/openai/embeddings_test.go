@@ -0,0 +1,78 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/devalexandre/mylangchaingo/embeddings/intent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingDoer answers the embedding requests and keeps their URL and payload.
+type recordingDoer struct {
+	urls     []string
+	payloads []map[string]any
+}
+
+func (d *recordingDoer) Do(req *http.Request) (*http.Response, error) {
+	var payload map[string]any
+	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+		return nil, err
+	}
+	d.urls = append(d.urls, req.URL.String())
+	d.payloads = append(d.payloads, payload)
+
+	rec := httptest.NewRecorder()
+	_, _ = io.WriteString(rec, `{"data":[`+strings.Repeat(`{"embedding":[1]},`, len(payload["input"].([]any))-1)+`{"embedding":[1]}]}`)
+	return rec.Result(), nil
+}
+
+func TestNvidiaEmbeddingInputTypes(t *testing.T) {
+	t.Setenv(tokenNvidiaEnvVarName, "nv-token")
+	doer := &recordingDoer{}
+	llm, err := New(WithAPIType(APITypeNvidia), WithHTTPClient(doer))
+	require.NoError(t, err)
+
+	embedder, err := intent.NewEmbedder(llm)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	_, err = embedder.EmbedDocuments(ctx, []string{"a passage", "another"})
+	require.NoError(t, err)
+	_, err = embedder.EmbedQuery(ctx, "a question")
+	require.NoError(t, err)
+	_, err = llm.CreateEmbedding(ctx, []string{"unknown"})
+	require.NoError(t, err)
+
+	require.Len(t, doer.payloads, 3)
+	assert.Equal(t, "passage", doer.payloads[0]["input_type"])
+	assert.Equal(t, "query", doer.payloads[1]["input_type"])
+	assert.Equal(t, "query", doer.payloads[2]["input_type"])
+	assert.Equal(t, "NV-Embed-QA", doer.payloads[0]["model"])
+	assert.Equal(t, "https://ai.api.nvidia.com/v1/retrieval/nvidia/embeddings", doer.urls[0])
+}
+
+func TestOpenAIEmbedding(t *testing.T) {
+	t.Parallel()
+
+	doer := &recordingDoer{}
+	llm, err := New(WithToken("test-token"), WithModel("gpt-4o"), WithBaseURL("https://example.com/v1"),
+		WithEmbeddingModel("text-embedding-3-small"), WithHTTPClient(doer))
+	require.NoError(t, err)
+
+	embedder, err := intent.NewEmbedder(llm)
+	require.NoError(t, err)
+	_, err = embedder.EmbedQuery(context.Background(), "a question")
+	require.NoError(t, err)
+
+	require.Len(t, doer.payloads, 1)
+	assert.Equal(t, "https://example.com/v1/embeddings", doer.urls[0])
+	assert.Equal(t, "text-embedding-3-small", doer.payloads[0]["model"])
+	assert.NotContains(t, doer.payloads[0], "input_type")
+}
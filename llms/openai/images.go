@@ -0,0 +1,193 @@
+package openai
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/devalexandre/mylangchaingo/llms/openai/internal/openaiclient"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// ImageResponseFormat is the format in which generated images are returned.
+type ImageResponseFormat string
+
+const (
+	// ImageResponseFormatURL returns a temporary URL for each image.
+	ImageResponseFormatURL ImageResponseFormat = "url"
+	// ImageResponseFormatB64JSON returns the image bytes, exposed as llms.BinaryContent.
+	ImageResponseFormatB64JSON ImageResponseFormat = "b64_json"
+)
+
+// Image is an image returned by the images API. URL is set when the image was
+// requested as ImageResponseFormatURL, Content when requested as
+// ImageResponseFormatB64JSON.
+type Image struct {
+	URL           string
+	Content       *llms.BinaryContent
+	RevisedPrompt string
+}
+
+type imageOptions struct {
+	model          string
+	n              int
+	size           string
+	quality        string
+	style          string
+	responseFormat ImageResponseFormat
+	user           string
+	mask           io.Reader
+}
+
+// ImageOption is a functional option for the images API.
+type ImageOption func(*imageOptions)
+
+// WithImageModel sets the model used to generate images, e.g. "dall-e-3".
+// Defaults to "dall-e-2".
+func WithImageModel(model string) ImageOption {
+	return func(o *imageOptions) {
+		o.model = model
+	}
+}
+
+// WithImageCount sets how many images to generate.
+func WithImageCount(n int) ImageOption {
+	return func(o *imageOptions) {
+		o.n = n
+	}
+}
+
+// WithImageSize sets the size of the generated images, e.g. "1024x1024".
+func WithImageSize(size string) ImageOption {
+	return func(o *imageOptions) {
+		o.size = size
+	}
+}
+
+// WithImageQuality sets the quality of the generated images ("standard" or "hd").
+// Only used when generating images.
+func WithImageQuality(quality string) ImageOption {
+	return func(o *imageOptions) {
+		o.quality = quality
+	}
+}
+
+// WithImageStyle sets the style of the generated images ("vivid" or "natural").
+// Only used when generating images.
+func WithImageStyle(style string) ImageOption {
+	return func(o *imageOptions) {
+		o.style = style
+	}
+}
+
+// WithImageResponseFormat sets whether images are returned as URLs or as binary content.
+// Defaults to ImageResponseFormatURL.
+func WithImageResponseFormat(format ImageResponseFormat) ImageOption {
+	return func(o *imageOptions) {
+		o.responseFormat = format
+	}
+}
+
+// WithImageUser sets the end-user identifier sent to OpenAI.
+func WithImageUser(user string) ImageOption {
+	return func(o *imageOptions) {
+		o.user = user
+	}
+}
+
+// WithImageMask sets the mask used by EditImage. Fully transparent areas of
+// the mask indicate where the image should be edited.
+func WithImageMask(mask io.Reader) ImageOption {
+	return func(o *imageOptions) {
+		o.mask = mask
+	}
+}
+
+func newImageOptions(opts ...ImageOption) *imageOptions {
+	o := &imageOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// GenerateImage generates images from the given prompt.
+func (o *LLM) GenerateImage(ctx context.Context, prompt string, options ...ImageOption) ([]Image, error) {
+	opts := newImageOptions(options...)
+
+	resp, err := o.client.CreateImage(ctx, &openaiclient.ImageRequest{
+		Model:          opts.model,
+		Prompt:         prompt,
+		N:              opts.n,
+		Size:           opts.size,
+		Quality:        opts.quality,
+		Style:          opts.style,
+		ResponseFormat: string(opts.responseFormat),
+		User:           opts.user,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return imagesFromResponse(resp)
+}
+
+// EditImage edits the given image according to the prompt. Use WithImageMask
+// to restrict the edit to an area of the image.
+func (o *LLM) EditImage(ctx context.Context, image io.Reader, prompt string, options ...ImageOption) ([]Image, error) {
+	opts := newImageOptions(options...)
+
+	resp, err := o.client.EditImage(ctx, &openaiclient.ImageEditRequest{
+		Image:          image,
+		Mask:           opts.mask,
+		Prompt:         prompt,
+		Model:          opts.model,
+		N:              opts.n,
+		Size:           opts.size,
+		ResponseFormat: string(opts.responseFormat),
+		User:           opts.user,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return imagesFromResponse(resp)
+}
+
+// CreateImageVariation creates variations of the given image.
+func (o *LLM) CreateImageVariation(ctx context.Context, image io.Reader, options ...ImageOption) ([]Image, error) {
+	opts := newImageOptions(options...)
+
+	resp, err := o.client.CreateImageVariation(ctx, &openaiclient.ImageVariationRequest{
+		Image:          image,
+		Model:          opts.model,
+		N:              opts.n,
+		Size:           opts.size,
+		ResponseFormat: string(opts.responseFormat),
+		User:           opts.user,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return imagesFromResponse(resp)
+}
+
+// imagesFromResponse converts the client response, decoding base64 images into binary content.
+func imagesFromResponse(resp *openaiclient.ImageResponse) ([]Image, error) {
+	images := make([]Image, 0, len(resp.Data))
+	for i, d := range resp.Data {
+		img := Image{URL: d.URL, RevisedPrompt: d.RevisedPrompt}
+		if d.B64JSON != "" {
+			data, err := base64.StdEncoding.DecodeString(d.B64JSON)
+			if err != nil {
+				return nil, fmt.Errorf("decode image %d: %w", i, err)
+			}
+			img.Content = &llms.BinaryContent{
+				MIMEType: http.DetectContentType(data),
+				Data:     data,
+			}
+		}
+		images = append(images, img)
+	}
+	return images, nil
+}
@@ -0,0 +1,97 @@
+package openai
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// a 1x1 transparent png.
+var testPNG, _ = base64.StdEncoding.DecodeString( //nolint:gochecknoglobals
+	"iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAQAAAC1HAwCAAAAC0lEQVR42mNkYAAAAAYAAjCB0C8AAAAASUVORK5CYII=")
+
+func TestGenerateImage(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/images/generations", r.URL.Path)
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+
+		var payload map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		assert.Equal(t, "a red fox", payload["prompt"])
+		assert.Equal(t, "dall-e-3", payload["model"])
+		assert.Equal(t, "b64_json", payload["response_format"])
+
+		_, _ = io.WriteString(w, `{"created":1,"data":[{"b64_json":"`+
+			base64.StdEncoding.EncodeToString(testPNG)+`","revised_prompt":"a small red fox"}]}`)
+	}))
+	defer srv.Close()
+
+	llm, err := New(WithToken("test-token"), WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	images, err := llm.GenerateImage(context.Background(), "a red fox",
+		WithImageModel("dall-e-3"),
+		WithImageResponseFormat(ImageResponseFormatB64JSON),
+	)
+	require.NoError(t, err)
+	require.Len(t, images, 1)
+	require.NotNil(t, images[0].Content)
+	assert.Equal(t, "image/png", images[0].Content.MIMEType)
+	assert.Equal(t, testPNG, images[0].Content.Data)
+	assert.Equal(t, "a small red fox", images[0].RevisedPrompt)
+}
+
+func TestEditImageWithMask(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/images/edits", r.URL.Path)
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		assert.Equal(t, "add a hat", r.FormValue("prompt"))
+		assert.Equal(t, "2", r.FormValue("n"))
+		assert.Len(t, r.MultipartForm.File["image"], 1)
+		assert.Len(t, r.MultipartForm.File["mask"], 1)
+
+		_, _ = io.WriteString(w, `{"created":1,"data":[{"url":"https://example.com/1.png"},{"url":"https://example.com/2.png"}]}`)
+	}))
+	defer srv.Close()
+
+	llm, err := New(WithToken("test-token"), WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	images, err := llm.EditImage(context.Background(), strings.NewReader(string(testPNG)), "add a hat",
+		WithImageMask(strings.NewReader(string(testPNG))),
+		WithImageCount(2),
+	)
+	require.NoError(t, err)
+	require.Len(t, images, 2)
+	assert.Equal(t, "https://example.com/2.png", images[1].URL)
+	assert.Nil(t, images[1].Content)
+}
+
+func TestCreateImageVariationAPIError(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = io.WriteString(w, `{"error":{"message":"invalid image","type":"invalid_request_error"}}`)
+	}))
+	defer srv.Close()
+
+	llm, err := New(WithToken("test-token"), WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	_, err = llm.CreateImageVariation(context.Background(), strings.NewReader("not an image"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid image")
+}
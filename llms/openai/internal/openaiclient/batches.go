@@ -0,0 +1,120 @@
+package openaiclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/devalexandre/langsmithgo"
+)
+
+// BatchRequest is a request to create a batch from an uploaded JSONL file.
+type BatchRequest struct {
+	InputFileID      string            `json:"input_file_id"`
+	Endpoint         string            `json:"endpoint"`
+	CompletionWindow string            `json:"completion_window"`
+	Metadata         map[string]string `json:"metadata,omitempty"`
+}
+
+// BatchError is an error of a single line of the input file.
+type BatchError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Param   string `json:"param,omitempty"`
+	Line    int    `json:"line,omitempty"`
+}
+
+// BatchErrors are the validation errors of the input file.
+type BatchErrors struct {
+	Data []BatchError `json:"data"`
+}
+
+// BatchRequestCounts are the request counts of a batch.
+type BatchRequestCounts struct {
+	Total     int `json:"total"`
+	Completed int `json:"completed"`
+	Failed    int `json:"failed"`
+}
+
+// Batch is a batch job.
+type Batch struct {
+	ID               string             `json:"id"`
+	Object           string             `json:"object"`
+	Endpoint         string             `json:"endpoint"`
+	Errors           *BatchErrors       `json:"errors,omitempty"`
+	InputFileID      string             `json:"input_file_id"`
+	CompletionWindow string             `json:"completion_window"`
+	Status           string             `json:"status"`
+	OutputFileID     string             `json:"output_file_id,omitempty"`
+	ErrorFileID      string             `json:"error_file_id,omitempty"`
+	CreatedAt        int64              `json:"created_at"`
+	CompletedAt      int64              `json:"completed_at,omitempty"`
+	FailedAt         int64              `json:"failed_at,omitempty"`
+	ExpiredAt        int64              `json:"expired_at,omitempty"`
+	CancelledAt      int64              `json:"cancelled_at,omitempty"`
+	RequestCounts    BatchRequestCounts `json:"request_counts"`
+	Metadata         map[string]string  `json:"metadata,omitempty"`
+}
+
+// CreateBatch creates a batch.
+func (c *Client) CreateBatch(ctx context.Context, r *BatchRequest) (*Batch, error) {
+	if r.CompletionWindow == "" {
+		r.CompletionWindow = "24h"
+	}
+
+	payloadBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, fmt.Errorf("marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.buildResourceURL("/batches"), bytes.NewReader(payloadBytes))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(req)
+
+	if err := c.traceStart("OpenAI - Create Batch", langsmithgo.Chain, map[string]interface{}{
+		"InputFileID": r.InputFileID,
+		"Endpoint":    r.Endpoint,
+		"Metadata":    r.Metadata,
+	}); err != nil {
+		return nil, err
+	}
+
+	var batch Batch
+	if err := c.doJSON(req, &batch); err != nil {
+		return nil, err
+	}
+
+	if err := c.traceEnd(map[string]interface{}{"output": batch}); err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+// RetrieveBatch returns the current state of a batch.
+func (c *Client) RetrieveBatch(ctx context.Context, batchID string) (*Batch, error) {
+	return c.batchRequest(ctx, http.MethodGet, "/batches/"+url.PathEscape(batchID))
+}
+
+// CancelBatch cancels an in-progress batch.
+func (c *Client) CancelBatch(ctx context.Context, batchID string) (*Batch, error) {
+	return c.batchRequest(ctx, http.MethodPost, "/batches/"+url.PathEscape(batchID)+"/cancel")
+}
+
+func (c *Client) batchRequest(ctx context.Context, method, suffix string) (*Batch, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.buildResourceURL(suffix), nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(req)
+
+	var batch Batch
+	if err := c.doJSON(req, &batch); err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
@@ -19,6 +19,13 @@ const (
 	defaultEmbeddingModelNvidia = "NV-Embed-QA"
 )
 
+// Input types of the NVIDIA embedding models, which embed queries and
+// passages differently.
+const (
+	InputTypeQuery   = "query"
+	InputTypePassage = "passage"
+)
+
 type embeddingPayload struct {
 	Model     string   `json:"model"`
 	Input     []string `json:"input"`
@@ -42,13 +49,16 @@ type embeddingResponsePayload struct {
 // nolint:lll
 func (c *Client) createEmbedding(ctx context.Context, payload *embeddingPayload) (*embeddingResponsePayload, error) {
 
-	if c.baseURL == "" {
-		c.baseURL = defaultBaseURL
-	}
-
-	if c.apiType == APITypeOpenAI {
-		payload.Model = c.embeddingsModel
-		c.baseURL = defaultEmbeddingURLNvidia
+	url := c.buildURL("/embeddings", c.embeddingsModel)
+	if c.apiType == APITypeNvidia {
+		url = defaultEmbeddingURLNvidia + "/embeddings"
+		payload.Model = c.EmbeddingModel()
+		if payload.InputType == "" {
+			payload.InputType = InputTypeQuery
+		}
+	} else {
+		// only the NVIDIA API knows the input types
+		payload.InputType = ""
 	}
 
 	payloadBytes, err := json.Marshal(payload)
@@ -56,15 +66,7 @@ func (c *Client) createEmbedding(ctx context.Context, payload *embeddingPayload)
 		return nil, fmt.Errorf("marshal payload: %w", err)
 	}
 
-	if c.apiType == APITypeNvidia {
-		payloadNvidia := c.nvidiaEmbedding(payload)
-		payloadBytes, err = json.Marshal(payloadNvidia)
-		if err != nil {
-			return nil, fmt.Errorf("marshal payload: %w", err)
-		}
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.buildURL("/embeddings", c.embeddingsModel), bytes.NewReader(payloadBytes))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payloadBytes))
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
@@ -147,21 +149,3 @@ func (c *Client) createEmbedding(ctx context.Context, payload *embeddingPayload)
 
 	return &response, nil
 }
-
-// nvidiaEmbedding is a helper function to set the correct parameters for the Nvidia API.
-// It sets the correct base URL, model, and input type.
-func (c *Client) nvidiaEmbedding(payload *embeddingPayload) embeddingPayload {
-	payload.InputType = "query"
-
-	if c.apiType == APITypeNvidia {
-		c.baseURL = defaultEmbeddingURLNvidia
-		if c.embeddingsModel == "" {
-			payload.Model = defaultEmbeddingModelNvidia
-		}
-		if c.embeddingsModel != "" {
-			payload.Model = c.embeddingsModel
-		}
-	}
-
-	return *payload
-}
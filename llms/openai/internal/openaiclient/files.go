@@ -0,0 +1,114 @@
+package openaiclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/devalexandre/langsmithgo"
+	"github.com/devalexandre/mylangchaingo/internal/formdata"
+)
+
+// File is a file stored with the Files API.
+type File struct {
+	ID        string `json:"id"`
+	Object    string `json:"object"`
+	Bytes     int64  `json:"bytes"`
+	CreatedAt int64  `json:"created_at"`
+	Filename  string `json:"filename"`
+	Purpose   string `json:"purpose"`
+	Status    string `json:"status,omitempty"`
+}
+
+// FileUploadRequest is a request to upload a file.
+type FileUploadRequest struct {
+	Filename string
+	Purpose  string
+	Content  io.Reader
+}
+
+// UploadFile uploads a file. The content is streamed to the API, so it is
+// never fully held in memory.
+func (c *Client) UploadFile(ctx context.Context, r *FileUploadRequest) (*File, error) {
+	body, contentType := formdata.StreamFile(map[string]string{"purpose": r.Purpose}, r.Filename, r.Content)
+	defer body.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.buildResourceURL("/files"), body)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(req)
+	req.Header.Set("Content-Type", contentType)
+
+	if err := c.traceStart("OpenAI - Upload File", langsmithgo.Tool, map[string]interface{}{
+		"Filename": r.Filename,
+		"Purpose":  r.Purpose,
+	}); err != nil {
+		return nil, err
+	}
+
+	var file File
+	if err := c.doJSON(req, &file); err != nil {
+		return nil, err
+	}
+
+	if err := c.traceEnd(map[string]interface{}{"output": file}); err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+// RetrieveFile returns the metadata of a file.
+func (c *Client) RetrieveFile(ctx context.Context, fileID string) (*File, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		c.buildResourceURL("/files/"+url.PathEscape(fileID)), nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(req)
+
+	var file File
+	if err := c.doJSON(req, &file); err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+// FileContent returns the content of a file. The caller must close it.
+func (c *Client) FileContent(ctx context.Context, fileID string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		c.buildResourceURL("/files/"+url.PathEscape(fileID)+"/content"), nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(req)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// DeleteFile deletes a file.
+func (c *Client) DeleteFile(ctx context.Context, fileID string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete,
+		c.buildResourceURL("/files/"+url.PathEscape(fileID)), nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(req)
+
+	var response struct {
+		Deleted bool `json:"deleted"`
+	}
+	if err := c.doJSON(req, &response); err != nil {
+		return err
+	}
+	if !response.Deleted {
+		return fmt.Errorf("file %s was not deleted", fileID) // nolint:goerr113
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package openaiclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/devalexandre/langsmithgo"
+)
+
+const (
+	defaultImageModel = "dall-e-2"
+)
+
+// ImageRequest is a request to generate images from a prompt.
+type ImageRequest struct {
+	Model          string `json:"model,omitempty"`
+	Prompt         string `json:"prompt"`
+	N              int    `json:"n,omitempty"`
+	Size           string `json:"size,omitempty"`
+	Quality        string `json:"quality,omitempty"`
+	Style          string `json:"style,omitempty"`
+	ResponseFormat string `json:"response_format,omitempty"`
+	User           string `json:"user,omitempty"`
+}
+
+// ImageEditRequest is a request to edit an image given a prompt and an optional mask.
+type ImageEditRequest struct {
+	Image          io.Reader
+	Mask           io.Reader
+	Prompt         string
+	Model          string
+	N              int
+	Size           string
+	ResponseFormat string
+	User           string
+}
+
+// ImageVariationRequest is a request to create variations of an image.
+type ImageVariationRequest struct {
+	Image          io.Reader
+	Model          string
+	N              int
+	Size           string
+	ResponseFormat string
+	User           string
+}
+
+// ImageData is a single generated image. Either URL or B64JSON is set,
+// depending on the requested response format.
+type ImageData struct {
+	URL           string `json:"url,omitempty"`
+	B64JSON       string `json:"b64_json,omitempty"`
+	RevisedPrompt string `json:"revised_prompt,omitempty"`
+}
+
+// ImageResponse is a response of the images API.
+type ImageResponse struct {
+	Created int64       `json:"created"`
+	Data    []ImageData `json:"data"`
+}
+
+// CreateImage generates images from a prompt.
+func (c *Client) CreateImage(ctx context.Context, r *ImageRequest) (*ImageResponse, error) {
+	if r.Model == "" {
+		r.Model = defaultImageModel
+	}
+
+	payloadBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, fmt.Errorf("marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		c.buildURL("/images/generations", r.Model), bytes.NewReader(payloadBytes))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(req)
+
+	return c.sendImageRequest(req, "OpenAI - Create Image", map[string]interface{}{
+		"Prompt": r.Prompt,
+		"Model":  r.Model,
+		"N":      r.N,
+		"Size":   r.Size,
+	})
+}
+
+// EditImage edits an image given a prompt. Transparent areas of the mask,
+// or of the image itself when no mask is given, are the ones edited.
+func (c *Client) EditImage(ctx context.Context, r *ImageEditRequest) (*ImageResponse, error) {
+	if r.Model == "" {
+		r.Model = defaultImageModel
+	}
+
+	body, contentType, err := newMultipartBody(map[string]string{
+		"prompt":          r.Prompt,
+		"model":           r.Model,
+		"n":               formatInt(r.N),
+		"size":            r.Size,
+		"response_format": r.ResponseFormat,
+		"user":            r.User,
+	}, formFile{field: "image", filename: "image.png", content: r.Image},
+		formFile{field: "mask", filename: "mask.png", content: r.Mask})
+	if err != nil {
+		return nil, fmt.Errorf("build multipart body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.buildURL("/images/edits", r.Model), body)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(req)
+	req.Header.Set("Content-Type", contentType)
+
+	return c.sendImageRequest(req, "OpenAI - Edit Image", map[string]interface{}{
+		"Prompt": r.Prompt,
+		"Model":  r.Model,
+		"N":      r.N,
+		"Size":   r.Size,
+		"Mask":   r.Mask != nil,
+	})
+}
+
+// CreateImageVariation creates variations of the given image.
+func (c *Client) CreateImageVariation(ctx context.Context, r *ImageVariationRequest) (*ImageResponse, error) {
+	if r.Model == "" {
+		r.Model = defaultImageModel
+	}
+
+	body, contentType, err := newMultipartBody(map[string]string{
+		"model":           r.Model,
+		"n":               formatInt(r.N),
+		"size":            r.Size,
+		"response_format": r.ResponseFormat,
+		"user":            r.User,
+	}, formFile{field: "image", filename: "image.png", content: r.Image})
+	if err != nil {
+		return nil, fmt.Errorf("build multipart body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.buildURL("/images/variations", r.Model), body)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(req)
+	req.Header.Set("Content-Type", contentType)
+
+	return c.sendImageRequest(req, "OpenAI - Create Image Variation", map[string]interface{}{
+		"Model": r.Model,
+		"N":     r.N,
+		"Size":  r.Size,
+	})
+}
+
+func (c *Client) sendImageRequest(req *http.Request, name string, inputs map[string]interface{}) (*ImageResponse, error) {
+	if err := c.traceStart(name, langsmithgo.LLM, inputs); err != nil {
+		return nil, err
+	}
+
+	var response ImageResponse
+	if err := c.doJSON(req, &response); err != nil {
+		return nil, err
+	}
+	if len(response.Data) == 0 {
+		return nil, ErrEmptyResponse
+	}
+
+	// images can be large, only the urls and revised prompts are traced.
+	outputs := make([]map[string]string, len(response.Data))
+	for i, d := range response.Data {
+		outputs[i] = map[string]string{"url": d.URL, "revised_prompt": d.RevisedPrompt}
+	}
+	if err := c.traceEnd(map[string]interface{}{"output": outputs}); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+func formatInt(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
@@ -0,0 +1,67 @@
+package openaiclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/devalexandre/langsmithgo"
+)
+
+// ModerationRequest is a request to classify texts against the usage policies.
+type ModerationRequest struct {
+	Model string   `json:"model,omitempty"`
+	Input []string `json:"input"`
+}
+
+// ModerationResult is the classification of a single input.
+type ModerationResult struct {
+	Flagged        bool               `json:"flagged"`
+	Categories     map[string]bool    `json:"categories"`
+	CategoryScores map[string]float64 `json:"category_scores"`
+}
+
+// ModerationResponse is a response of the moderations API.
+type ModerationResponse struct {
+	ID      string             `json:"id"`
+	Model   string             `json:"model"`
+	Results []ModerationResult `json:"results"`
+}
+
+// CreateModeration classifies the inputs. Results are in the same order as the inputs.
+func (c *Client) CreateModeration(ctx context.Context, r *ModerationRequest) (*ModerationResponse, error) {
+	payloadBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, fmt.Errorf("marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		c.buildURL("/moderations", r.Model), bytes.NewReader(payloadBytes))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(req)
+
+	if err := c.traceStart("OpenAI - Create Moderation", langsmithgo.LLM, map[string]interface{}{
+		"Input": r.Input,
+		"Model": r.Model,
+	}); err != nil {
+		return nil, err
+	}
+
+	var response ModerationResponse
+	if err := c.doJSON(req, &response); err != nil {
+		return nil, err
+	}
+	if len(response.Results) != len(r.Input) {
+		return nil, fmt.Errorf("moderation returned %d results for %d inputs", len(response.Results), len(r.Input))
+	}
+
+	if err := c.traceEnd(map[string]interface{}{"output": response}); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
@@ -116,6 +116,8 @@ func (c *Client) CreateCompletion(ctx context.Context, r *CompletionRequest) (*C
 type EmbeddingRequest struct {
 	Model string   `json:"model"`
 	Input []string `json:"input"`
+	// InputType is InputTypeQuery or InputTypePassage, for the NVIDIA API.
+	InputType string `json:"input_type,omitempty"`
 }
 
 // CreateEmbedding creates embeddings.
@@ -125,8 +127,9 @@ func (c *Client) CreateEmbedding(ctx context.Context, r *EmbeddingRequest) ([][]
 	}
 
 	resp, err := c.createEmbedding(ctx, &embeddingPayload{
-		Model: r.Model,
-		Input: r.Input,
+		Model:     r.Model,
+		Input:     r.Input,
+		InputType: r.InputType,
 	})
 	if err != nil {
 		return nil, err
@@ -146,13 +149,7 @@ func (c *Client) CreateEmbedding(ctx context.Context, r *EmbeddingRequest) ([][]
 
 // CreateChat creates chat request.
 func (c *Client) CreateChat(ctx context.Context, r *ChatRequest) (*ChatCompletionResponse, error) {
-	if r.Model == "" {
-		if c.Model == "" {
-			r.Model = defaultChatModel
-		} else {
-			r.Model = c.Model
-		}
-	}
+	r.Model = c.ChatModel(r.Model)
 
 	resp, err := c.createChat(ctx, r)
 	if err != nil {
@@ -164,6 +161,30 @@ func (c *Client) CreateChat(ctx context.Context, r *ChatRequest) (*ChatCompletio
 	return resp, nil
 }
 
+// ChatModel returns the model used for chat requests: model itself when set,
+// otherwise the client model, falling back to the default chat model.
+func (c *Client) ChatModel(model string) string {
+	switch {
+	case model != "":
+		return model
+	case c.Model != "":
+		return c.Model
+	default:
+		return defaultChatModel
+	}
+}
+
+// EmbeddingModel returns the model used for embedding requests.
+func (c *Client) EmbeddingModel() string {
+	if c.embeddingsModel != "" {
+		return c.embeddingsModel
+	}
+	if c.apiType == APITypeNvidia {
+		return defaultEmbeddingModelNvidia
+	}
+	return defaultEmbeddingModel
+}
+
 func IsAzure(apiType APIType) bool {
 	return apiType == APITypeAzure || apiType == APITypeAzureAD
 }
@@ -186,7 +207,20 @@ func (c *Client) buildURL(suffix string, model string) string {
 	}
 
 	// open ai implement:
-	return fmt.Sprintf("%s%s", c.baseURL, suffix)
+	baseURL := c.baseURL
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	return fmt.Sprintf("%s%s", baseURL, suffix)
+}
+
+// buildResourceURL builds the URL of resources that are not tied to a model
+// deployment, such as files and batches.
+func (c *Client) buildResourceURL(suffix string) string {
+	if IsAzure(c.apiType) {
+		return fmt.Sprintf("%s/openai%s?api-version=%s", strings.TrimRight(c.baseURL, "/"), suffix, c.apiVersion)
+	}
+	return c.buildURL(suffix, "")
 }
 
 func (c *Client) buildAzureURL(suffix string, model string) string {
@@ -0,0 +1,147 @@
+package openaiclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"runtime"
+
+	"github.com/devalexandre/langsmithgo"
+	"github.com/devalexandre/mylangchaingo"
+	"github.com/google/uuid"
+)
+
+// formFile is a file part of a multipart request.
+type formFile struct {
+	field    string
+	filename string
+	content  io.Reader
+}
+
+// newMultipartBody builds a multipart body with the given fields and files.
+// Empty fields are skipped.
+func newMultipartBody(fields map[string]string, files ...formFile) (*bytes.Buffer, string, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	for _, f := range files {
+		if f.content == nil {
+			continue
+		}
+		part, err := writer.CreateFormFile(f.field, f.filename)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := io.Copy(part, f.content); err != nil {
+			return nil, "", fmt.Errorf("copy %s: %w", f.field, err)
+		}
+	}
+
+	for key, value := range fields {
+		if value == "" {
+			continue
+		}
+		if err := writer.WriteField(key, value); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+	return body, writer.FormDataContentType(), nil
+}
+
+// do sends the request and returns the response when the API answered with
+// a 2xx status code. Otherwise the body is closed and the API error returned.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	r, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+
+	if r.StatusCode < http.StatusOK || r.StatusCode >= http.StatusMultipleChoices {
+		defer r.Body.Close()
+		msg := fmt.Sprintf("API returned unexpected status code: %d", r.StatusCode)
+
+		// No need to check the error here: if it fails, we'll just return the
+		// status code.
+		var errResp errorMessage
+		if err := json.NewDecoder(r.Body).Decode(&errResp); err != nil {
+			return nil, errors.New(msg) // nolint:goerr113
+		}
+
+		return nil, fmt.Errorf("%s: %s", msg, errResp.Error.Message) // nolint:goerr113
+	}
+
+	return r, nil
+}
+
+// doJSON sends the request and decodes the JSON response into v.
+func (c *Client) doJSON(req *http.Request, v any) error {
+	r, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	defer r.Body.Close()
+
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+// traceStart opens a langsmith run for the current request when tracing is enabled.
+func (c *Client) traceStart(name string, runType langsmithgo.RunType, inputs map[string]interface{}) error {
+	if c.langsmithClient == nil {
+		return nil
+	}
+	if c.langsmithgoParentId == "" {
+		c.langsmithgoParentId = mylangchaingo.GetParentId()
+	}
+
+	err := c.langsmithClient.Run(&langsmithgo.RunPayload{
+		Name:        name,
+		SessionName: os.Getenv("LANGCHAIN_PROJECT_NAME"),
+		RunType:     runType,
+		RunID:       mylangchaingo.GetRunId(),
+		ParentID:    c.langsmithgoParentId,
+		Inputs:      inputs,
+		Extras: map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"go_version": runtime.Version(),
+				"platform":   runtime.GOOS,
+				"arch":       runtime.GOARCH,
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error running langsmith: %w", err)
+	}
+	return nil
+}
+
+// traceEnd records the outputs of the current langsmith run and moves on to a new run id.
+func (c *Client) traceEnd(outputs map[string]interface{}) error {
+	if c.langsmithClient == nil {
+		return nil
+	}
+
+	err := c.langsmithClient.Run(&langsmithgo.RunPayload{
+		RunID:   mylangchaingo.GetRunId(),
+		Outputs: outputs,
+	})
+	if err != nil {
+		return fmt.Errorf("error running langsmith: %w", err)
+	}
+
+	// move on to the next run id so following calls are nested under this one
+	mylangchaingo.SetParentId(mylangchaingo.GetRunId())
+	mylangchaingo.SetRunId(uuid.New().String())
+	return nil
+}
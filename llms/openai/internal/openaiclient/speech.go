@@ -0,0 +1,80 @@
+package openaiclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/devalexandre/langsmithgo"
+)
+
+const (
+	defaultSpeechModel = "tts-1"
+	defaultSpeechVoice = "alloy"
+)
+
+// SpeechRequest is a request to turn text into audio.
+type SpeechRequest struct {
+	Model          string  `json:"model"`
+	Input          string  `json:"input"`
+	Voice          string  `json:"voice"`
+	ResponseFormat string  `json:"response_format,omitempty"`
+	Speed          float64 `json:"speed,omitempty"`
+}
+
+// CreateSpeech generates audio for the input text and streams it to w.
+// It returns the number of bytes written.
+func (c *Client) CreateSpeech(ctx context.Context, r *SpeechRequest, w io.Writer) (int64, error) {
+	if r.Model == "" {
+		r.Model = defaultSpeechModel
+	}
+	if r.Voice == "" {
+		r.Voice = defaultSpeechVoice
+	}
+
+	payloadBytes, err := json.Marshal(r)
+	if err != nil {
+		return 0, fmt.Errorf("marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		c.buildURL("/audio/speech", r.Model), bytes.NewReader(payloadBytes))
+	if err != nil {
+		return 0, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(req)
+
+	err = c.traceStart("OpenAI - Create Speech", langsmithgo.LLM, map[string]interface{}{
+		"Input":          r.Input,
+		"Model":          r.Model,
+		"Voice":          r.Voice,
+		"ResponseFormat": r.ResponseFormat,
+		"Speed":          r.Speed,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	n, err := io.Copy(w, resp.Body)
+	if err != nil {
+		return n, fmt.Errorf("copy audio: %w", err)
+	}
+
+	if err := c.traceEnd(map[string]interface{}{
+		"bytes":        n,
+		"content_type": resp.Header.Get("Content-Type"),
+	}); err != nil {
+		return n, err
+	}
+
+	return n, nil
+}
@@ -0,0 +1,43 @@
+package openai
+
+import (
+	"context"
+
+	"github.com/devalexandre/mylangchaingo/llms/openai/internal/openaiclient"
+)
+
+// ModerationResult is the classification of a single input by the moderations API.
+type ModerationResult = openaiclient.ModerationResult
+
+type moderationOptions struct {
+	model string
+}
+
+// ModerationOption is a functional option for the moderations API.
+type ModerationOption func(*moderationOptions)
+
+// WithModerationModel sets the moderation model, e.g. "omni-moderation-latest".
+// If not set, the API default is used.
+func WithModerationModel(model string) ModerationOption {
+	return func(o *moderationOptions) {
+		o.model = model
+	}
+}
+
+// Moderate classifies the inputs against OpenAI's usage policies.
+// Results are returned in the same order as the inputs.
+func (o *LLM) Moderate(ctx context.Context, inputs []string, options ...ModerationOption) ([]ModerationResult, error) {
+	opts := &moderationOptions{}
+	for _, opt := range options {
+		opt(opts)
+	}
+
+	resp, err := o.client.CreateModeration(ctx, &openaiclient.ModerationRequest{
+		Model: opts.model,
+		Input: inputs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Results, nil
+}
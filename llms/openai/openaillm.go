@@ -3,6 +3,7 @@ package openai
 import (
 	"context"
 	"fmt"
+	"github.com/devalexandre/mylangchaingo/embeddings/intent"
 	"github.com/devalexandre/mylangchaingo/llms/openai/internal/openaiclient"
 
 	"github.com/tmc/langchaingo/callbacks"
@@ -54,6 +55,27 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 		opt(&opts)
 	}
 
+	req, err := newChatRequest(messages, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := o.client.CreateChat(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	response, err := contentResponseFromChat(result)
+	if err != nil {
+		return nil, err
+	}
+	if o.CallbacksHandler != nil {
+		o.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, response)
+	}
+	return response, nil
+}
+
+// newChatRequest converts the messages and call options into a chat request.
+func newChatRequest(messages []llms.MessageContent, opts llms.CallOptions) (*openaiclient.ChatRequest, error) { //nolint:cyclop,funlen
 	chatMsgs := make([]*ChatMessage, 0, len(messages))
 	for _, mc := range messages {
 		msg := &ChatMessage{MultiContent: mc.Parts}
@@ -133,10 +155,11 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 		req.Tools = append(req.Tools, t)
 	}
 
-	result, err := o.client.CreateChat(ctx, req)
-	if err != nil {
-		return nil, err
-	}
+	return req, nil
+}
+
+// contentResponseFromChat converts a chat completion into a content response.
+func contentResponseFromChat(result *openaiclient.ChatCompletionResponse) (*llms.ContentResponse, error) {
 	if len(result.Choices) == 0 {
 		return nil, ErrEmptyResponse
 	}
@@ -178,18 +201,17 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 			}
 		}
 	}
-	response := &llms.ContentResponse{Choices: choices}
-	if o.CallbacksHandler != nil {
-		o.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, response)
-	}
-	return response, nil
+	return &llms.ContentResponse{Choices: choices}, nil
 }
 
-// CreateEmbedding creates embeddings for the given input texts.
+// CreateEmbedding creates embeddings for the given input texts. The NVIDIA
+// models embed them as passages or queries after the intent of the context,
+// see the intent package, and as queries when it is unknown.
 func (o *LLM) CreateEmbedding(ctx context.Context, inputTexts []string) ([][]float32, error) {
 	embeddings, err := o.client.CreateEmbedding(ctx, &openaiclient.EmbeddingRequest{
-		Input: inputTexts,
-		Model: o.client.Model,
+		Input:     inputTexts,
+		Model:     o.client.EmbeddingModel(),
+		InputType: embeddingInputType(ctx),
 	})
 	if err != nil {
 		return nil, err
@@ -203,6 +225,17 @@ func (o *LLM) CreateEmbedding(ctx context.Context, inputTexts []string) ([][]flo
 	return embeddings, nil
 }
 
+// embeddingInputType returns the NVIDIA input type of the context intent.
+func embeddingInputType(ctx context.Context) string {
+	switch intent.FromContext(ctx) {
+	case intent.Query:
+		return openaiclient.InputTypeQuery
+	case intent.Document:
+		return openaiclient.InputTypePassage
+	}
+	return ""
+}
+
 // ExtractToolParts extracts the tool parts from a message.
 func ExtractToolParts(msg *ChatMessage) ([]llms.ContentPart, []llms.ToolCall) {
 	var content []llms.ContentPart
@@ -0,0 +1,97 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/devalexandre/mylangchaingo/llms/openai/internal/openaiclient"
+)
+
+// ErrInvalidSpeechSpeed is returned when the speech speed is outside the range supported by the API.
+var ErrInvalidSpeechSpeed = errors.New("speech speed must be between 0.25 and 4.0")
+
+// SpeechVoice is a voice used by the text-to-speech API.
+type SpeechVoice string
+
+const (
+	SpeechVoiceAlloy   SpeechVoice = "alloy"
+	SpeechVoiceEcho    SpeechVoice = "echo"
+	SpeechVoiceFable   SpeechVoice = "fable"
+	SpeechVoiceOnyx    SpeechVoice = "onyx"
+	SpeechVoiceNova    SpeechVoice = "nova"
+	SpeechVoiceShimmer SpeechVoice = "shimmer"
+)
+
+// SpeechFormat is the audio format returned by the text-to-speech API.
+type SpeechFormat string
+
+const (
+	SpeechFormatMP3  SpeechFormat = "mp3"
+	SpeechFormatOpus SpeechFormat = "opus"
+	SpeechFormatAAC  SpeechFormat = "aac"
+	SpeechFormatFLAC SpeechFormat = "flac"
+	SpeechFormatWAV  SpeechFormat = "wav"
+	// SpeechFormatPCM is raw 24kHz 16-bit signed little-endian samples, without a header.
+	SpeechFormatPCM SpeechFormat = "pcm"
+)
+
+type speechOptions struct {
+	model  string
+	voice  SpeechVoice
+	format SpeechFormat
+	speed  float64
+}
+
+// SpeechOption is a functional option for the text-to-speech API.
+type SpeechOption func(*speechOptions)
+
+// WithSpeechModel sets the text-to-speech model, e.g. "tts-1-hd". Defaults to "tts-1".
+func WithSpeechModel(model string) SpeechOption {
+	return func(o *speechOptions) {
+		o.model = model
+	}
+}
+
+// WithSpeechVoice sets the voice used to read the text. Defaults to SpeechVoiceAlloy.
+func WithSpeechVoice(voice SpeechVoice) SpeechOption {
+	return func(o *speechOptions) {
+		o.voice = voice
+	}
+}
+
+// WithSpeechFormat sets the audio format. Defaults to SpeechFormatMP3.
+func WithSpeechFormat(format SpeechFormat) SpeechOption {
+	return func(o *speechOptions) {
+		o.format = format
+	}
+}
+
+// WithSpeechSpeed sets the speed of the generated audio, from 0.25 to 4.0. Defaults to 1.0.
+func WithSpeechSpeed(speed float64) SpeechOption {
+	return func(o *speechOptions) {
+		o.speed = speed
+	}
+}
+
+// CreateSpeech reads the input text aloud and streams the audio to w.
+// It is the counterpart of documentloaders.WhisperOpenAILoader.
+func (o *LLM) CreateSpeech(ctx context.Context, input string, w io.Writer, options ...SpeechOption) error {
+	opts := &speechOptions{}
+	for _, opt := range options {
+		opt(opts)
+	}
+
+	if opts.speed != 0 && (opts.speed < 0.25 || opts.speed > 4.0) {
+		return ErrInvalidSpeechSpeed
+	}
+
+	_, err := o.client.CreateSpeech(ctx, &openaiclient.SpeechRequest{
+		Model:          opts.model,
+		Input:          input,
+		Voice:          string(opts.voice),
+		ResponseFormat: string(opts.format),
+		Speed:          opts.speed,
+	}, w)
+	return err
+}
@@ -0,0 +1,56 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateSpeech(t *testing.T) {
+	t.Parallel()
+
+	audio := bytes.Repeat([]byte{0x1, 0x2, 0x3}, 1024)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/audio/speech", r.URL.Path)
+
+		var payload map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		assert.Equal(t, "Olá, mundo", payload["input"])
+		assert.Equal(t, "tts-1", payload["model"])
+		assert.Equal(t, "nova", payload["voice"])
+		assert.Equal(t, "wav", payload["response_format"])
+		assert.InDelta(t, 1.5, payload["speed"], 0.001)
+
+		w.Header().Set("Content-Type", "audio/wav")
+		_, _ = w.Write(audio)
+	}))
+	defer srv.Close()
+
+	llm, err := New(WithToken("test-token"), WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	err = llm.CreateSpeech(context.Background(), "Olá, mundo", &out,
+		WithSpeechVoice(SpeechVoiceNova),
+		WithSpeechFormat(SpeechFormatWAV),
+		WithSpeechSpeed(1.5),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, audio, out.Bytes())
+}
+
+func TestCreateSpeechInvalidSpeed(t *testing.T) {
+	t.Parallel()
+
+	llm, err := New(WithToken("test-token"), WithBaseURL("http://127.0.0.1:0"))
+	require.NoError(t, err)
+
+	err = llm.CreateSpeech(context.Background(), "hi", &bytes.Buffer{}, WithSpeechSpeed(5))
+	require.ErrorIs(t, err, ErrInvalidSpeechSpeed)
+}